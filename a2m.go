@@ -0,0 +1,167 @@
+package gofasta
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// A2M and A3M are FASTA-based alignment formats used by HMMER and HH-suite.
+// Uppercase letters and '-' are match states that belong to the columns of
+// the model or reference sequence. Lowercase letters are insertions
+// relative to the reference and '.' is a gap in an insert column.
+// In A2M, insert columns are padded with '.' so that all rows have the same
+// length. A3M omits the '.' padding, which makes rows differ in length.
+
+// ReadA3MFile reads an A2M or A3M file into a fully aligned Alignment.
+// Compressed files are decompressed transparently.
+func ReadA3MFile(path string) (Alignment, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadA3M(file)
+}
+
+// ReadA3M reads an A2M- or A3M-formatted io.Reader stream and expands it
+// into a fully aligned Alignment using ExpandA3M.
+func ReadA3M(r io.Reader) (Alignment, error) {
+	a, err := ReadFasta(r, false)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandA3M(a)
+}
+
+// isInsertState tells whether the character belongs to an insert column.
+func isInsertState(c rune) bool {
+	return c == '.' || unicode.IsLower(c)
+}
+
+// ExpandA3M converts an A3M or A2M alignment into an alignment in which all
+// rows have the same length. Insertions of each row are padded with '.' up
+// to the longest insertion at the same position among all rows. All rows
+// must have the same number of match states.
+func ExpandA3M(a Alignment) (Alignment, error) {
+	if len(a) == 0 {
+		return Alignment{}, nil
+	}
+	// inserts[i][k] holds the insertion of row i before match state k
+	inserts := make([][]string, len(a))
+	matches := make([][]rune, len(a))
+	for i, s := range a {
+		var insert strings.Builder
+		for _, c := range s.Sequence() {
+			switch {
+			case c == '.':
+			case unicode.IsLower(c):
+				insert.WriteRune(c)
+			default:
+				inserts[i] = append(inserts[i], insert.String())
+				matches[i] = append(matches[i], c)
+				insert.Reset()
+			}
+		}
+		inserts[i] = append(inserts[i], insert.String())
+		if len(matches[i]) != len(matches[0]) {
+			return nil, fmt.Errorf("sequence %q has %d match states, expected %d", s.ID(), len(matches[i]), len(matches[0]))
+		}
+	}
+	maxInserts := make([]int, len(inserts[0]))
+	for i := range inserts {
+		for k, insert := range inserts[i] {
+			if n := len([]rune(insert)); n > maxInserts[k] {
+				maxInserts[k] = n
+			}
+		}
+	}
+	expanded := make(Alignment, len(a))
+	for i, s := range a {
+		var buff strings.Builder
+		for k, insert := range inserts[i] {
+			buff.WriteString(insert)
+			buff.WriteString(strings.Repeat(".", maxInserts[k]-len([]rune(insert))))
+			if k < len(matches[i]) {
+				buff.WriteRune(matches[i][k])
+			}
+		}
+		expanded[i] = NewCharSequence(s.ID(), s.Description(), buff.String())
+		copyAttributes(expanded[i], s)
+	}
+	return expanded, nil
+}
+
+// RemoveInserts removes all insert states from an A2M or A3M alignment,
+// keeping only the match columns. All rows must have the same number of
+// match states.
+func RemoveInserts(a Alignment) (Alignment, error) {
+	result := make(Alignment, len(a))
+	matchCount := -1
+	for i, s := range a {
+		seq := strings.Map(func(c rune) rune {
+			if isInsertState(c) {
+				return -1
+			}
+			return c
+		}, s.Sequence())
+		if n := len([]rune(seq)); matchCount >= 0 && n != matchCount {
+			return nil, fmt.Errorf("sequence %q has %d match states, expected %d", s.ID(), n, matchCount)
+		} else if matchCount < 0 {
+			matchCount = n
+		}
+		result[i] = NewCharSequence(s.ID(), s.Description(), seq)
+		copyAttributes(result[i], s)
+	}
+	return result, nil
+}
+
+// CollapseToA2M converts an aligned Alignment into A2M using the row at
+// index ref as the reference. Columns where the reference has a gap become
+// insert columns, in which letters are lowercased and gaps are replaced by
+// '.'. In the remaining match columns, letters are uppercased and gaps are
+// written as '-'. The alignment must be valid, see Alignment.Valid.
+func CollapseToA2M(a Alignment, ref int, gapChar string) (Alignment, error) {
+	if !a.Valid() {
+		return nil, ErrInvalidAlignment
+	}
+	if ref < 0 || ref >= len(a) {
+		return nil, fmt.Errorf("reference index %d out of range", ref)
+	}
+	gapRune := []rune(gapChar)[0]
+	isGap := func(c rune) bool {
+		return c == gapRune || c == '-' || c == '.'
+	}
+	refSeq := []rune(a[ref].Sequence())
+	result := make(Alignment, len(a))
+	for i, s := range a {
+		seq := []rune(s.Sequence())
+		for j, c := range seq {
+			switch {
+			case isGap(refSeq[j]) && isGap(c):
+				seq[j] = '.'
+			case isGap(refSeq[j]):
+				seq[j] = unicode.ToLower(c)
+			case isGap(c):
+				seq[j] = '-'
+			default:
+				seq[j] = unicode.ToUpper(c)
+			}
+		}
+		result[i] = NewCharSequence(s.ID(), s.Description(), string(seq))
+		copyAttributes(result[i], s)
+	}
+	return result, nil
+}
+
+// A2MToA3M removes the '.' padding of insert columns, converting an A2M
+// alignment into A3M.
+func A2MToA3M(a Alignment) Alignment {
+	result := make(Alignment, len(a))
+	for i, s := range a {
+		result[i] = NewCharSequence(s.ID(), s.Description(), strings.Replace(s.Sequence(), ".", "", -1))
+		copyAttributes(result[i], s)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package gofasta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadA3M(t *testing.T) {
+	r := strings.NewReader("#A3M from hhblits\n" +
+		">query\nMKV-LA\n" +
+		">hit1 desc\nMKaaV-LA\n" +
+		">hit2\nM-V-cLAgh\n")
+	a, err := ReadA3M(r)
+	if err != nil {
+		t.Fatalf("ReadA3M: unexpected error %v", err)
+	}
+	exp := []string{
+		"MK..V-.LA..",
+		"MKaaV-.LA..",
+		"M-..V-cLAgh",
+	}
+	for i, s := range a {
+		if s.Sequence() != exp[i] {
+			t.Errorf("ReadA3M: expected %#v, actual %#v", exp[i], s.Sequence())
+		}
+	}
+	if !a.Valid() {
+		t.Errorf("ReadA3M: expected valid alignment")
+	}
+	if a[1].Description() != "desc" {
+		t.Errorf("ReadA3M: expected description to be kept")
+	}
+}
+
+func TestExpandA3M_Error(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("a", "", "MKV"),
+		NewCharSequence("b", "", "MKVL"),
+	}
+	if _, err := ExpandA3M(a); err == nil {
+		t.Errorf("ExpandA3M: expected error for different match state counts")
+	}
+}
+
+func TestRemoveInserts(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("a", "", "M..KV-.LA.."),
+		NewCharSequence("b", "", "MaaKV-LA"),
+	}
+	actual, err := RemoveInserts(a)
+	if err != nil {
+		t.Fatalf("RemoveInserts: unexpected error %v", err)
+	}
+	for _, s := range actual {
+		if s.Sequence() != "MKV-LA" {
+			t.Errorf("RemoveInserts: expected %#v, actual %#v", "MKV-LA", s.Sequence())
+		}
+	}
+}
+
+func TestCollapseToA2M(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("ref", "", "M--KV-LA-"),
+		NewCharSequence("b", "", "MAAK--LAG"),
+		NewCharSequence("c", "", "mc-kvala-"),
+	}
+	actual, err := CollapseToA2M(a, 0, "-")
+	if err != nil {
+		t.Fatalf("CollapseToA2M: unexpected error %v", err)
+	}
+	exp := []string{"M..KV.LA.", "MaaK-.LAg", "Mc.KVaLA."}
+	for i, s := range actual {
+		if s.Sequence() != exp[i] {
+			t.Errorf("CollapseToA2M: expected %#v, actual %#v", exp[i], s.Sequence())
+		}
+	}
+	a3m := A2MToA3M(actual)
+	if a3m[1].Sequence() != "MaaK-LAg" {
+		t.Errorf("A2MToA3M: expected %#v, actual %#v", "MaaK-LAg", a3m[1].Sequence())
+	}
+	expanded, err := ExpandA3M(a3m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range expanded {
+		if s.Sequence() != exp[i] {
+			t.Errorf("ExpandA3M: expected %#v, actual %#v", exp[i], s.Sequence())
+		}
+	}
+	if _, err := CollapseToA2M(a, 3, "-"); err == nil {
+		t.Errorf("CollapseToA2M: expected error for reference out of range")
+	}
+}
@@ -2,7 +2,7 @@ package gofasta
 
 import (
 	"bytes"
-	"fmt"
+	"io"
 	"os"
 )
 
@@ -64,30 +64,66 @@ func (a Alignment) Valid() bool {
 
 // ToFastaFile saves the sequence alignment to a FASTA file.
 func (a Alignment) ToFastaFile(path string) {
+	if err := a.WriteFastaFile(path); err != nil {
+		panic(err)
+	}
+}
+
+// WriteFastaFile saves the sequence alignment to a FASTA file.
+// Unlike ToFastaFile, errors are returned instead of causing a panic.
+func (a Alignment) WriteFastaFile(path string) error {
 	f, err := os.Create(path)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	defer f.Close()
+	if err = a.WriteFasta(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-	_, err = f.WriteString(a.ToFasta())
+// WriteCompressedFastaFile saves the sequence alignment to a FASTA file
+// compressed using codec c. Use BGZF to create files that can be indexed.
+func (a Alignment) WriteCompressedFastaFile(path string, c Compression) error {
+	f, err := os.Create(path)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	f.Sync()
+	cw, err := NewCompressWriter(f, c)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err = a.WriteFasta(cw); err != nil {
+		f.Close()
+		return err
+	}
+	if err = cw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteFasta writes the sequence alignment in the FASTA format to w.
+// Each sequence is written on a single line. Use FastaWriter for wrapped
+// output.
+func (a Alignment) WriteFasta(w io.Writer) error {
+	return NewFastaWriter(w).WriteAlignment(a)
 }
 
 // ToFasta writes the sequence alignment as a string in the FASTA format.
 func (a Alignment) ToFasta() string {
-	// Append each Sequence in Alignment
 	var buff bytes.Buffer
-	for _, s := range a {
-		if len(s.Description()) > 0 {
-			buff.WriteString(fmt.Sprintf(">%s %s\n", s.ID(), s.Description()))
-		} else {
-			buff.WriteString(fmt.Sprintf(">%s\n", s.ID()))
-		}
-		buff.WriteString(fmt.Sprintf("%s\n", s.Sequence()))
-	}
+	NewFastaWriter(&buff).WriteAlignment(a)
 	return buff.String()
 }
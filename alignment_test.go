@@ -2,6 +2,8 @@ package gofasta
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -157,3 +159,29 @@ func TestAlignment_Valid_False(t *testing.T) {
 		t.Errorf("Valid: expected false but instead got true")
 	}
 }
+
+func TestAlignment_WriteFastaFile(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("test1", "test", "TTT---TTCTTATTG"),
+		NewCharSequence("test2", "", "TTT---TTCTTTTTG"),
+	}
+	path := filepath.Join(t.TempDir(), "test.fa")
+	if err := a.WriteFastaFile(path); err != nil {
+		t.Fatalf("WriteFastaFile: unexpected error %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := a.ToFasta(); exp != string(b) {
+		t.Errorf("WriteFastaFile: expected %#v, actual %#v", exp, string(b))
+	}
+}
+
+func TestAlignment_WriteFastaFile_Error(t *testing.T) {
+	a := Alignment{NewCharSequence("test1", "", "TTT")}
+	path := filepath.Join(t.TempDir(), "missing", "test.fa")
+	if err := a.WriteFastaFile(path); err == nil {
+		t.Errorf("WriteFastaFile: expected error for missing directory")
+	}
+}
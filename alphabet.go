@@ -0,0 +1,140 @@
+package gofasta
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidChar is returned when a sequence contains a character that is
+// not part of its alphabet.
+var ErrInvalidChar = errors.New("invalid character")
+
+// Alphabet is a set of symbols that can appear in a sequence. Letters are
+// the residue symbols, including ambiguity codes if any. Gap holds the
+// symbols used for alignment gaps and Missing the symbols used for unknown
+// residues. Symbols are compared case-insensitively.
+type Alphabet struct {
+	Name    string
+	Letters string
+	Gap     string
+	Missing string
+}
+
+// Predefined alphabets, ordered from the most to the least restrictive.
+var (
+	// DNA contains the four unambiguous nucleotides.
+	DNA = NewAlphabet("DNA", "ACGT", "-", "N")
+	// IUPACDNA contains the nucleotides and the IUPAC ambiguity codes.
+	IUPACDNA = NewAlphabet("IUPAC DNA", "ACGTRYSWKMBDHVN", "-.", "N?")
+	// RNA contains the ribonucleotides and the IUPAC ambiguity codes.
+	RNA = NewAlphabet("RNA", "ACGURYSWKMBDHVN", "-.", "N?")
+	// Protein contains the 20 standard amino acids and the stop symbol.
+	Protein = NewAlphabet("protein", "ACDEFGHIKLMNPQRSTVWY*", "-", "X")
+	// AmbiguousProtein adds selenocysteine (U), pyrrolysine (O) and the
+	// ambiguity codes B, Z, J and X to Protein.
+	AmbiguousProtein = NewAlphabet("ambiguous protein", "ACDEFGHIKLMNPQRSTVWY*UOBZJX", "-.", "X?")
+)
+
+// detectableAlphabets lists the alphabets tried by DetectAlphabet in order.
+var detectableAlphabets = []*Alphabet{DNA, IUPACDNA, RNA, Protein, AmbiguousProtein}
+
+// NewAlphabet creates a custom alphabet. Symbols are stored in uppercase.
+func NewAlphabet(name, letters, gap, missing string) *Alphabet {
+	return &Alphabet{
+		Name:    name,
+		Letters: strings.ToUpper(letters),
+		Gap:     gap,
+		Missing: strings.ToUpper(missing),
+	}
+}
+
+// ValidChars returns all symbols of the alphabet, that is its letters, gap
+// and missing symbols.
+func (a *Alphabet) ValidChars() string {
+	return a.Letters + a.Gap + a.Missing
+}
+
+// Contains returns true if c is a letter, gap or missing symbol of the
+// alphabet.
+func (a *Alphabet) Contains(c rune) bool {
+	return strings.ContainsRune(a.ValidChars(), unicode.ToUpper(c))
+}
+
+// IsGap returns true if c is a gap symbol of the alphabet.
+func (a *Alphabet) IsGap(c rune) bool {
+	return strings.ContainsRune(a.Gap, c)
+}
+
+// IsMissing returns true if c is a missing symbol of the alphabet.
+func (a *Alphabet) IsMissing(c rune) bool {
+	return strings.ContainsRune(a.Missing, unicode.ToUpper(c))
+}
+
+// Validate returns an error wrapping ErrInvalidChar for the first character
+// of seq that is not part of the alphabet.
+func (a *Alphabet) Validate(seq string) error {
+	for i, c := range []rune(seq) {
+		if !a.Contains(c) {
+			return fmt.Errorf("%w %q at position %d for %s alphabet", ErrInvalidChar, c, i+1, a.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateSequence is like Validate but includes the ID of the sequence in
+// the error.
+func (a *Alphabet) ValidateSequence(s Sequence) error {
+	if err := a.Validate(s.Sequence()); err != nil {
+		return fmt.Errorf("%s: %w", s.ID(), err)
+	}
+	return nil
+}
+
+// String returns the name of the alphabet.
+func (a *Alphabet) String() string {
+	return a.Name
+}
+
+// DetectAlphabet returns the most restrictive predefined alphabet that
+// contains every character of the sequence, trying DNA, IUPACDNA, RNA,
+// Protein and AmbiguousProtein in that order. It returns nil if none of
+// them matches. Note that short protein sequences made only of A, C, G and
+// T are detected as DNA.
+func DetectAlphabet(s Sequence) *Alphabet {
+	return detectAlphabet(Alignment{s})
+}
+
+// DetectAlphabet returns the most restrictive predefined alphabet that
+// contains every character of all sequences in the alignment. See
+// DetectAlphabet for details.
+func (a Alignment) DetectAlphabet() *Alphabet {
+	return detectAlphabet(a)
+}
+
+func detectAlphabet(a Alignment) *Alphabet {
+	var used []rune
+	seen := make(map[rune]bool)
+	for _, s := range a {
+		for _, c := range s.Sequence() {
+			if !seen[c] {
+				seen[c] = true
+				used = append(used, c)
+			}
+		}
+	}
+	for _, alphabet := range detectableAlphabets {
+		valid := true
+		for _, c := range used {
+			if !alphabet.Contains(c) {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			return alphabet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package gofasta
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAlphabet_Validate(t *testing.T) {
+	cases := []struct {
+		alphabet *Alphabet
+		seq      string
+		valid    bool
+	}{
+		{DNA, "ACGT-acgtN", true},
+		{DNA, "ACGTR", false},
+		{IUPACDNA, "ACGTRYN-.?", true},
+		{IUPACDNA, "ACGU", false},
+		{RNA, "ACGU-n", true},
+		{Protein, "MKV*-X", true},
+		{Protein, "MKVB", false},
+		{AmbiguousProtein, "MKVBZJUO", true},
+		{NewAlphabet("binary", "01", "-", "?"), "0101-?", true},
+		{NewAlphabet("binary", "01", "-", "?"), "012", false},
+	}
+	for _, c := range cases {
+		err := c.alphabet.Validate(c.seq)
+		if c.valid && err != nil {
+			t.Errorf("Validate: unexpected error %v for %#v", err, c.seq)
+		} else if !c.valid && !errors.Is(err, ErrInvalidChar) {
+			t.Errorf("Validate: expected ErrInvalidChar for %#v, actual %v", c.seq, err)
+		}
+	}
+}
+
+func TestAlphabet_Symbols(t *testing.T) {
+	if !IUPACDNA.IsGap('.') || IUPACDNA.IsGap('N') {
+		t.Errorf("IsGap: unexpected result")
+	}
+	if !Protein.IsMissing('x') || Protein.IsMissing('-') {
+		t.Errorf("IsMissing: unexpected result")
+	}
+}
+
+func TestDetectAlphabet(t *testing.T) {
+	cases := []struct {
+		seq string
+		exp *Alphabet
+	}{
+		{"ACGT--", DNA},
+		{"ACGTRN", IUPACDNA},
+		{"ACGU", RNA},
+		{"MKVLA*", Protein},
+		{"MKVBZ", AmbiguousProtein},
+		{"12345", nil},
+	}
+	for _, c := range cases {
+		if actual := DetectAlphabet(NewCharSequence("a", "", c.seq)); actual != c.exp {
+			t.Errorf("DetectAlphabet: expected %v, actual %v for %#v", c.exp, actual, c.seq)
+		}
+	}
+	a := Alignment{
+		NewCharSequence("a", "", "ACGT"),
+		NewCharSequence("b", "", "ACGR"),
+	}
+	if actual := a.DetectAlphabet(); actual != IUPACDNA {
+		t.Errorf("DetectAlphabet: expected %v, actual %v", IUPACDNA, actual)
+	}
+}
+
+func TestFastaReader_SetAlphabet(t *testing.T) {
+	r := NewFastaReader(strings.NewReader(">a\nACGT\n>b\nMKVL\n"), false)
+	r.SetAlphabet(DNA)
+	for r.Next() {
+	}
+	var verr *ValidationError
+	if !errors.As(r.Err(), &verr) {
+		t.Fatalf("SetAlphabet: expected ValidationError, actual %v", r.Err())
+	}
+	if len(verr.Issues) != 4 || verr.Issues[0].ID != "b" {
+		t.Errorf("SetAlphabet: unexpected issues %#v", verr.Issues)
+	}
+}
@@ -0,0 +1,122 @@
+package gofasta
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// copyAttributes sets the attributes of src on dst. Values are not deep
+// copied.
+func copyAttributes(dst, src Sequence) {
+	for key, value := range src.Attributes() {
+		dst.SetAttribute(key, value)
+	}
+}
+
+// ReadAttributeTable reads a delimited table whose first row holds the
+// column names and whose first column holds sequence IDs. The comma
+// argument is the field delimiter, such as ',' for CSV or '\t' for TSV.
+// It returns the attributes keyed by sequence ID and then by column name.
+// Empty cells are omitted.
+func ReadAttributeTable(r io.Reader, comma rune) (map[string]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.Comment = '#'
+	if comma == '\t' {
+		reader.LazyQuotes = true
+	}
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return nil, &ParseError{Line: 1, Reason: "missing header row"}
+	} else if err != nil {
+		return nil, &ParseError{Line: 1, Reason: "invalid header row", Err: err}
+	}
+	table := make(map[string]map[string]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, &ParseError{Line: line, Reason: "invalid row", Err: err}
+		}
+		id := record[0]
+		if _, ok := table[id]; ok {
+			return nil, &ParseError{Line: line, ID: id, Reason: "duplicate ID"}
+		}
+		attrs := make(map[string]string)
+		for i, value := range record[1:] {
+			if len(value) > 0 {
+				attrs[columns[i+1]] = value
+			}
+		}
+		table[id] = attrs
+	}
+	return table, nil
+}
+
+// JoinAttributes reads a delimited attribute table from r (see
+// ReadAttributeTable) and sets its values as attributes of the sequences
+// with matching IDs. Values are always stored as strings; numbers and other
+// types are not converted. It returns the IDs of the sequences that are not
+// in the table.
+func (a Alignment) JoinAttributes(r io.Reader, comma rune) (missing []string, err error) {
+	table, err := ReadAttributeTable(r, comma)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range a {
+		attrs, ok := table[s.ID()]
+		if !ok {
+			missing = append(missing, s.ID())
+			continue
+		}
+		for key, value := range attrs {
+			s.SetAttribute(key, value)
+		}
+	}
+	return
+}
+
+// JoinAttributesFile is like JoinAttributes but reads the table from a
+// file. Files ending in .tsv, .tab or .txt are read as tab-delimited, other
+// files as comma-delimited, ignoring a compression suffix such as .gz.
+// Compressed files are decompressed transparently.
+func (a Alignment) JoinAttributesFile(path string) ([]string, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	name := strings.ToLower(path)
+	switch filepath.Ext(name) {
+	case ".gz", ".bgz", ".bz2":
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	comma := ','
+	switch filepath.Ext(name) {
+	case ".tsv", ".tab", ".txt":
+		comma = '\t'
+	}
+	missing, err := a.JoinAttributes(file, comma)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return missing, nil
+}
+
+// Filter returns the sequences for which keep returns true. The sequences
+// are not copied, so their attributes are carried over.
+func (a Alignment) Filter(keep func(Sequence) bool) Alignment {
+	var result Alignment
+	for _, s := range a {
+		if keep(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package gofasta
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAttributeTable(t *testing.T) {
+	data := "id,country,date\na,Japan,2020-01-01\nb,,2020-02-01\n"
+	table, err := ReadAttributeTable(strings.NewReader(data), ',')
+	if err != nil {
+		t.Fatalf("ReadAttributeTable: unexpected error %v", err)
+	}
+	exp := map[string]map[string]string{
+		"a": {"country": "Japan", "date": "2020-01-01"},
+		"b": {"date": "2020-02-01"},
+	}
+	if !reflect.DeepEqual(exp, table) {
+		t.Errorf("ReadAttributeTable: expected %#v, actual %#v", exp, table)
+	}
+	for _, data := range []string{"", "id,x\na,1\na,2\n", "id,x\na,1,2\n"} {
+		if _, err := ReadAttributeTable(strings.NewReader(data), ','); err == nil {
+			t.Errorf("ReadAttributeTable: expected error for %#v", data)
+		}
+	}
+}
+
+func TestAlignment_JoinAttributesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.tsv")
+	if err := os.WriteFile(path, []byte("id\tlineage\na\tB.1\nc\tA.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := Alignment{
+		NewCharSequence("a", "", "ATG"),
+		NewCodonSequence("b", "", "ATG"),
+	}
+	missing, err := a.JoinAttributesFile(path)
+	if err != nil {
+		t.Fatalf("JoinAttributesFile: unexpected error %v", err)
+	}
+	if exp := []string{"b"}; !reflect.DeepEqual(exp, missing) {
+		t.Errorf("JoinAttributesFile: expected %#v, actual %#v", exp, missing)
+	}
+	if v, _ := a[0].Attribute("lineage"); v != "B.1" {
+		t.Errorf("JoinAttributesFile: expected %#v, actual %#v", "B.1", v)
+	}
+}
+
+func TestAlignment_Filter(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("a", "", "ATG"),
+		NewCharSequence("b", "", "ATG"),
+	}
+	a[0].SetAttribute("keep", true)
+	filtered := a.Filter(func(s Sequence) bool {
+		keep, _ := s.Attribute("keep")
+		return keep == true
+	})
+	if len(filtered) != 1 || filtered[0].ID() != "a" {
+		t.Errorf("Filter: expected %#v, actual %#v", a[:1], filtered)
+	}
+	expanded, _ := ExpandA3M(filtered)
+	if v, _ := expanded[0].Attribute("keep"); v != true {
+		t.Errorf("ExpandA3M: expected attributes to be carried over")
+	}
+}
@@ -0,0 +1,186 @@
+package gofasta
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// CodonUsage maps codons to their counts or relative frequencies. Codons
+// are uppercase DNA.
+type CodonUsage map[string]float64
+
+// BackTranslateMethod selects how BackTranslate chooses codons.
+type BackTranslateMethod int
+
+const (
+	// BackTranslateDegenerate encodes each amino acid as the IUPAC
+	// consensus of all its codons, such as "GCN" for alanine. The
+	// consensus is taken position by position, so for amino acids whose
+	// codons differ at more than one position, and for stops, it also
+	// matches codons of other amino acids: leucine becomes "YTN", which
+	// includes the phenylalanine codons TTT and TTC, and stop becomes
+	// "TRR", which includes TGG for tryptophan. The protein of the
+	// returned sequence is built using ResolveCodon, so such codons
+	// translate to 'X' and the output does not always round-trip.
+	BackTranslateDegenerate BackTranslateMethod = iota
+	// BackTranslateMostFrequent uses the most frequent codon of each amino
+	// acid according to the codon usage table.
+	BackTranslateMostFrequent
+	// BackTranslateSample draws each codon at random, weighted by the codon
+	// usage table.
+	BackTranslateSample
+)
+
+// BackTranslateOptions configures BackTranslate.
+//
+// Code is the NCBI translation table ID; 0 selects the standard genetic
+// code. Usage is the codon usage table required by the
+// BackTranslateMostFrequent and BackTranslateSample methods. Seed
+// initializes the random number generator of BackTranslateSample so that
+// results can be reproduced.
+type BackTranslateOptions struct {
+	Code   int
+	Method BackTranslateMethod
+	Usage  CodonUsage
+	Seed   int64
+}
+
+// iupacCodes maps sets of nucleotides, in alphabetical order, to their IUPAC
+// code.
+var iupacCodes = func() map[string]byte {
+	m := make(map[string]byte)
+	for code, bases := range iupacExpansions {
+		m[bases] = code
+	}
+	return m
+}()
+
+// BackTranslate creates a CodonSequence encoding the protein sequence prot.
+// Gaps ('-') become "---" and unknown amino acids ('X') become "NNN". The
+// stop symbol '*' is encoded using the stop codons of the table. The
+// returned sequence uses the selected genetic code for its translation, and
+// its protein is translated using ResolveCodon so that ambiguous codons
+// encoding a single amino acid, such as "GCN", are translated.
+func BackTranslate(name, description, prot string, opts BackTranslateOptions) (*CodonSequence, error) {
+	code := StandardGeneticCode
+	if opts.Code != 0 {
+		var err error
+		if code, err = GeneticCodeByID(opts.Code); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Method != BackTranslateDegenerate && len(opts.Usage) == 0 {
+		return nil, fmt.Errorf("back-translation method %d requires a codon usage table", opts.Method)
+	}
+	synonymous := make(map[byte][]string)
+	for i, c := range Codons {
+		synonymous[code.AminoAcids[i]] = append(synonymous[code.AminoAcids[i]], c)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var buff bytes.Buffer
+	for i, aa := range []byte(strings.ToUpper(prot)) {
+		switch aa {
+		case '-':
+			buff.WriteString("---")
+			continue
+		case 'X':
+			buff.WriteString("NNN")
+			continue
+		}
+		codons := synonymous[aa]
+		if len(codons) == 0 {
+			return nil, fmt.Errorf("cannot back-translate %q at position %d", aa, i+1)
+		}
+		var codon string
+		switch opts.Method {
+		case BackTranslateDegenerate:
+			codon = degenerateCodon(codons)
+		case BackTranslateMostFrequent:
+			codon = mostFrequentCodon(codons, opts.Usage)
+		case BackTranslateSample:
+			codon = sampleCodon(codons, opts.Usage, rng)
+		default:
+			return nil, fmt.Errorf("unknown back-translation method %d", opts.Method)
+		}
+		if len(codon) == 0 {
+			return nil, fmt.Errorf("no codon usage for %q at position %d", aa, i+1)
+		}
+		buff.WriteString(codon)
+	}
+	s := &CodonSequence{code: code}
+	s.name = name
+	s.description = description
+	s.SetSequence(buff.String())
+	var resolved strings.Builder
+	for _, codon := range s.codons {
+		resolved.WriteString(code.ResolveCodon(codon))
+	}
+	s.prot = resolved.String()
+	return s, nil
+}
+
+// degenerateCodon returns the IUPAC consensus of the codons at each
+// position. The result can match codons that are not in codons.
+func degenerateCodon(codons []string) string {
+	codon := make([]byte, 3)
+	for pos := 0; pos < 3; pos++ {
+		set := make(map[byte]bool)
+		for _, c := range codons {
+			set[c[pos]] = true
+		}
+		bases := make([]string, 0, len(set))
+		for b := range set {
+			bases = append(bases, string(b))
+		}
+		sort.Strings(bases)
+		codon[pos] = iupacCodes[strings.Join(bases, "")]
+	}
+	return string(codon)
+}
+
+// mostFrequentCodon returns the codon with the highest usage. Ties are
+// broken by the order of the codons. It returns an empty string if none of
+// the codons is used.
+func mostFrequentCodon(codons []string, usage CodonUsage) (best string) {
+	max := 0.0
+	for _, c := range codons {
+		if usage[c] > max {
+			best, max = c, usage[c]
+		}
+	}
+	return
+}
+
+// sampleCodon draws a codon with probability proportional to its usage. It
+// returns an empty string if none of the codons is used.
+func sampleCodon(codons []string, usage CodonUsage, rng *rand.Rand) string {
+	total := 0.0
+	for _, c := range codons {
+		if usage[c] > 0 {
+			total += usage[c]
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	x := rng.Float64() * total
+	for _, c := range codons {
+		if usage[c] <= 0 {
+			continue
+		}
+		if x < usage[c] {
+			return c
+		}
+		x -= usage[c]
+	}
+	// guard against rounding errors
+	for i := len(codons) - 1; i >= 0; i-- {
+		if usage[codons[i]] > 0 {
+			return codons[i]
+		}
+	}
+	return ""
+}
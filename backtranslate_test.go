@@ -0,0 +1,82 @@
+package gofasta
+
+import "testing"
+
+func TestBackTranslate_Degenerate(t *testing.T) {
+	s, err := BackTranslate("p", "test", "MAL*-xW", BackTranslateOptions{})
+	if err != nil {
+		t.Fatalf("BackTranslate: unexpected error %v", err)
+	}
+	if exp := "ATGGCNYTNTRR---NNNTGG"; exp != s.Sequence() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Sequence())
+	}
+	if exp := "MAXX-XW"; exp != s.Prot() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if s.ID() != "p" || s.Description() != "test" || len(s.Codons()) != 7 {
+		t.Errorf("BackTranslate: unexpected sequence %#v", s)
+	}
+	s, _ = BackTranslate("p", "", "W", BackTranslateOptions{Code: 2})
+	if exp := "TGR"; exp != s.Sequence() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Sequence())
+	}
+	if _, err := BackTranslate("p", "", "MJ", BackTranslateOptions{}); err == nil {
+		t.Errorf("BackTranslate: expected error for unknown amino acid")
+	}
+}
+
+func TestBackTranslate_DegenerateLossy(t *testing.T) {
+	s, err := BackTranslate("p", "", "ALRS*", BackTranslateOptions{})
+	if err != nil {
+		t.Fatalf("BackTranslate: unexpected error %v", err)
+	}
+	if exp := "GCNYTNMGNWSNTRR"; exp != s.Sequence() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Sequence())
+	}
+	// GCN only encodes alanine, but the consensus codons of L, R, S and
+	// stop also match codons of other amino acids and translate to X
+	if exp := "AXXXX"; exp != s.Prot() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Prot())
+	}
+}
+
+func TestBackTranslate_Usage(t *testing.T) {
+	usage := CodonUsage{"ATG": 10, "GCT": 1, "GCC": 40, "GCA": 2, "TAA": 5}
+	s, err := BackTranslate("p", "", "MAA*", BackTranslateOptions{Method: BackTranslateMostFrequent, Usage: usage})
+	if err != nil {
+		t.Fatalf("BackTranslate: unexpected error %v", err)
+	}
+	if exp := "ATGGCCGCCTAA"; exp != s.Sequence() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Sequence())
+	}
+	if exp := "MAA*"; exp != s.Prot() {
+		t.Errorf("BackTranslate: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if _, err := BackTranslate("p", "", "MK", BackTranslateOptions{Method: BackTranslateMostFrequent, Usage: usage}); err == nil {
+		t.Errorf("BackTranslate: expected error for amino acid without usage")
+	}
+	if _, err := BackTranslate("p", "", "M", BackTranslateOptions{Method: BackTranslateSample}); err == nil {
+		t.Errorf("BackTranslate: expected error for missing usage table")
+	}
+}
+
+func TestBackTranslate_Sample(t *testing.T) {
+	usage := CodonUsage{"GCT": 1, "GCC": 1, "GCA": 0, "GCG": 0}
+	prot := "AAAAAAAAAAAAAAAAAAAA"
+	opts := BackTranslateOptions{Method: BackTranslateSample, Usage: usage, Seed: 42}
+	s1, err := BackTranslate("p", "", prot, opts)
+	if err != nil {
+		t.Fatalf("BackTranslate: unexpected error %v", err)
+	}
+	s2, _ := BackTranslate("p", "", prot, opts)
+	if s1.Sequence() != s2.Sequence() {
+		t.Errorf("BackTranslate: expected same result for same seed, actual %#v and %#v", s1.Sequence(), s2.Sequence())
+	}
+	counts := make(map[string]int)
+	for _, c := range s1.Codons() {
+		counts[c]++
+	}
+	if counts["GCA"] > 0 || counts["GCG"] > 0 || counts["GCT"] == 0 || counts["GCC"] == 0 {
+		t.Errorf("BackTranslate: unexpected codon counts %v", counts)
+	}
+}
@@ -13,11 +13,12 @@ type CharSequence struct {
 	name        string
 	description string
 	sequence    string
+	attributes  map[string]interface{}
 }
 
 // NewCharSequence contructs a new CharSequence.
 func NewCharSequence(name, description, sequence string) *CharSequence {
-	return &CharSequence{name, description, sequence}
+	return &CharSequence{name: name, description: description, sequence: sequence}
 }
 
 // ID returns the name of CharSequence.
@@ -30,6 +31,20 @@ func (s *CharSequence) Description() string {
 	return s.description
 }
 
+// Attribute returns the value of the named attribute of CharSequence and
+// whether it is set.
+func (s *CharSequence) Attribute(key string) (interface{}, bool) {
+	value, ok := s.attributes[key]
+	return value, ok
+}
+
+// Attributes returns the attributes of CharSequence keyed by name. The
+// returned map is shared with the sequence and is nil if no attribute has
+// been set.
+func (s *CharSequence) Attributes() map[string]interface{} {
+	return s.attributes
+}
+
 // Sequence returns the sequence of CharSequence.
 func (s *CharSequence) Sequence() string {
 	return s.sequence
@@ -50,6 +65,21 @@ func (s *CharSequence) SetDescription(title string) {
 	s.description = title
 }
 
+// SetAttribute sets the value of the named attribute of CharSequence.
+// Values can be of any type, such as a time.Time for a sample date or an int
+// for a read count.
+func (s *CharSequence) SetAttribute(key string, value interface{}) {
+	if s.attributes == nil {
+		s.attributes = make(map[string]interface{})
+	}
+	s.attributes[key] = value
+}
+
+// DeleteAttribute removes the named attribute from CharSequence.
+func (s *CharSequence) DeleteAttribute(key string) {
+	delete(s.attributes, key)
+}
+
 // SetSequence sets the sequence of CharSequence.
 func (s *CharSequence) SetSequence(seq string) {
 	s.sequence = seq
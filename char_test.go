@@ -6,7 +6,7 @@ func TestNewCharSequence(t *testing.T) {
 	name := "a"
 	desc := "test"
 	seq := "ATGGCGTAG"
-	exp := CharSequence{name, desc, seq}
+	exp := CharSequence{name: name, description: desc, sequence: seq}
 	actual := *NewCharSequence(name, desc, seq)
 
 	if exp.name != actual.name && exp.description != actual.description && exp.sequence != actual.sequence {
@@ -18,7 +18,7 @@ func TestCharSequence_Properties(t *testing.T) {
 	id := "a"
 	desc := "test"
 	seq := "ATGGCGTAG"
-	actual := CharSequence{id, desc, seq}
+	actual := CharSequence{name: id, description: desc, sequence: seq}
 
 	if id != actual.ID() {
 		t.Errorf("expected %#v, actual %#v", id, actual.ID())
@@ -32,7 +32,7 @@ func TestCharSequence_Properties(t *testing.T) {
 }
 
 func TestCharSequence_SetID(t *testing.T) {
-	seq := CharSequence{"a", "test", "ATGGCGTAG"}
+	seq := CharSequence{name: "a", description: "test", sequence: "ATGGCGTAG"}
 	exp := "x"
 	seq.SetID(exp)
 	if exp != seq.name {
@@ -41,7 +41,7 @@ func TestCharSequence_SetID(t *testing.T) {
 }
 
 func TestCharSequence_SetDescription(t *testing.T) {
-	seq := CharSequence{"a", "test", "ATGGCGTAG"}
+	seq := CharSequence{name: "a", description: "test", sequence: "ATGGCGTAG"}
 	exp := "test again"
 	seq.SetDescription(exp)
 	if exp != seq.description {
@@ -50,7 +50,7 @@ func TestCharSequence_SetDescription(t *testing.T) {
 }
 
 func TestCharSequence_SetSequence(t *testing.T) {
-	seq := CharSequence{"a", "test", "ATGGCGTAG"}
+	seq := CharSequence{name: "a", description: "test", sequence: "ATGGCGTAG"}
 	exp := "CCCCCCCCC"
 	seq.SetSequence(exp)
 	if exp != seq.sequence {
@@ -59,7 +59,7 @@ func TestCharSequence_SetSequence(t *testing.T) {
 }
 
 func TestCharSequence_ToUpper(t *testing.T) {
-	seq := CharSequence{"a", "test", "atggcgtag"}
+	seq := CharSequence{name: "a", description: "test", sequence: "atggcgtag"}
 	exp := "ATGGCGTAG"
 	seq.ToUpper()
 	if exp != seq.sequence {
@@ -68,7 +68,7 @@ func TestCharSequence_ToUpper(t *testing.T) {
 }
 
 func TestCharSequence_ToLower(t *testing.T) {
-	seq := CharSequence{"a", "test", "ATGGCGTAG"}
+	seq := CharSequence{name: "a", description: "test", sequence: "ATGGCGTAG"}
 	exp := "atggcgtag"
 	seq.ToLower()
 	if exp != seq.sequence {
@@ -77,7 +77,7 @@ func TestCharSequence_ToLower(t *testing.T) {
 }
 
 func TestCharSequence_Char(t *testing.T) {
-	seq := CharSequence{"a", "test", "GTGGCGTAG"}
+	seq := CharSequence{name: "a", description: "test", sequence: "GTGGCGTAG"}
 	exp := "A"
 	actual := seq.Char(7)
 	if exp != actual {
@@ -87,7 +87,7 @@ func TestCharSequence_Char(t *testing.T) {
 
 func TestCharSequence_UngappedCoords(t *testing.T) {
 	seq := "TTT---TTCTTATTG"
-	s := CharSequence{"test", "", seq}
+	s := CharSequence{name: "test", description: "", sequence: seq}
 	exp := []int{0, 1, 2, 6, 7, 8, 9, 10, 11, 12, 13, 14}
 
 	res := s.UngappedCoords("-")
@@ -103,7 +103,7 @@ func TestCharSequence_UngappedCoords(t *testing.T) {
 
 func TestCharSequence_UngappedPositionSlice(t *testing.T) {
 	seq := "TTT---TTCTTATTG"
-	s := CharSequence{"test", "", seq}
+	s := CharSequence{name: "test", description: "", sequence: seq}
 	exp := []int{0, 1, 2, -1, -1, -1, 3, 4, 5, 6, 7, 8, 9, 10, 11}
 
 	res := s.UngappedPositionSlice("-")
@@ -116,3 +116,19 @@ func TestCharSequence_UngappedPositionSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestCharSequence_Attributes(t *testing.T) {
+	s := NewCharSequence("test", "", "ATG")
+	if _, ok := s.Attribute("count"); ok {
+		t.Errorf("Attribute: expected missing attribute")
+	}
+	s.SetAttribute("count", 12)
+	s.SetAttribute("lineage", "B.1.1.7")
+	if v, ok := s.Attribute("count"); !ok || v != 12 {
+		t.Errorf("Attribute: expected %#v, actual %#v", 12, v)
+	}
+	s.DeleteAttribute("lineage")
+	if len(s.Attributes()) != 1 {
+		t.Errorf("Attributes: expected %d attributes, actual %d", 1, len(s.Attributes()))
+	}
+}
@@ -0,0 +1,231 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// clustalBlockWidth is the number of characters per sequence in each block
+// of Clustal output.
+const clustalBlockWidth = 60
+
+// clustalStrongGroups and clustalWeakGroups are the amino acid groups used
+// by Clustal to mark conserved columns with ':' and '.' respectively.
+var clustalStrongGroups = []string{
+	"STA", "NEQK", "NHQK", "NDEQ", "QHRK", "MILV", "MILF", "HY", "FYW",
+}
+var clustalWeakGroups = []string{
+	"CSA", "ATV", "SAG", "STNK", "STPA", "SGND", "SNDEQK", "NDEQHK", "NEQHRK", "FVLIM", "HFY",
+}
+
+// ReadClustalFile reads a Clustal (.aln) file into an Alignment.
+// Compressed files are decompressed transparently.
+func ReadClustalFile(path string, toCodon bool) (Alignment, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadClustal(file, toCodon)
+}
+
+// ReadClustal reads a Clustal-formatted io.Reader stream into an Alignment.
+// The header line, conservation lines and optional residue counts at the
+// end of sequence lines are ignored. If toCodon is true, sequences are
+// stored as CodonSequence, otherwise as CharSequence.
+func ReadClustal(r io.Reader, toCodon bool) (Alignment, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	var names []string
+	var seqs []*strings.Builder
+	index := make(map[string]int)
+	lineNum := 0
+	headerFound := false
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if !headerFound {
+			if len(strings.TrimSpace(line)) == 0 {
+				continue
+			}
+			if !strings.HasPrefix(line, "CLUSTAL") && !strings.Contains(line, "multiple sequence alignment") {
+				return nil, &ParseError{Line: lineNum, Reason: "missing CLUSTAL header"}
+			}
+			headerFound = true
+			continue
+		}
+		// Blank lines separate blocks, lines starting with whitespace hold
+		// the conservation symbols
+		if len(line) == 0 || unicode.IsSpace([]rune(line)[0]) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, &ParseError{Line: lineNum, Reason: "expected sequence name and sequence"}
+		}
+		if len(fields) == 3 {
+			if _, err := strconv.Atoi(fields[2]); err != nil {
+				return nil, &ParseError{Line: lineNum, ID: fields[0], Reason: "invalid residue count", Err: err}
+			}
+		}
+		i, ok := index[fields[0]]
+		if !ok {
+			i = len(names)
+			index[fields[0]] = i
+			names = append(names, fields[0])
+			seqs = append(seqs, new(strings.Builder))
+		}
+		seqs[i].WriteString(fields[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Line: lineNum, Reason: "cannot read input", Err: err}
+	}
+	if !headerFound {
+		return nil, &ParseError{Line: lineNum, Reason: "missing CLUSTAL header"}
+	}
+	a := make(Alignment, len(names))
+	for i, name := range names {
+		var err error
+		if a[i], err = newSequence(name, "", seqs[i].String(), toCodon); err != nil {
+			return nil, &ParseError{ID: name, Reason: "invalid codon sequence", Err: err}
+		}
+	}
+	return a, nil
+}
+
+// ClustalConservation returns the Clustal conservation line of the
+// alignment. Columns where all characters are identical are marked '*'.
+// Columns where all amino acids belong to the same strong or weak group
+// are marked ':' and '.' respectively, unless the alignment only contains
+// nucleotides. Columns containing a gap are left blank. Characters are
+// compared case-insensitively.
+func ClustalConservation(a Alignment, gapChar string) string {
+	if len(a) == 0 {
+		return ""
+	}
+	seqs := make([][]rune, len(a))
+	for i, s := range a {
+		seqs[i] = []rune(strings.ToUpper(s.Sequence()))
+	}
+	gapRune, _ := utf8.DecodeRuneInString(gapChar)
+	protein := false
+	for _, s := range seqs {
+		for _, c := range s {
+			if c != gapRune && c != '.' && !strings.ContainsRune("ACGTUN", c) {
+				protein = true
+			}
+		}
+	}
+	line := make([]rune, len(seqs[0]))
+	for j := range line {
+		column := make([]rune, 0, len(seqs))
+		for _, s := range seqs {
+			if j < len(s) {
+				column = append(column, s[j])
+			}
+		}
+		line[j] = clustalSymbol(column, gapRune, protein)
+	}
+	return string(line)
+}
+
+func clustalSymbol(column []rune, gapRune rune, protein bool) rune {
+	identical := true
+	for _, c := range column {
+		if c == gapRune || c == '.' {
+			return ' '
+		}
+		if c != column[0] {
+			identical = false
+		}
+	}
+	if identical {
+		return '*'
+	}
+	if !protein {
+		return ' '
+	}
+	inGroup := func(groups []string) bool {
+		for _, g := range groups {
+			all := true
+			for _, c := range column {
+				if !strings.ContainsRune(g, c) {
+					all = false
+					break
+				}
+			}
+			if all {
+				return true
+			}
+		}
+		return false
+	}
+	if inGroup(clustalStrongGroups) {
+		return ':'
+	}
+	if inGroup(clustalWeakGroups) {
+		return '.'
+	}
+	return ' '
+}
+
+// WriteClustalFile saves the sequence alignment to a Clustal file.
+func (a Alignment) WriteClustalFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = a.WriteClustal(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteClustal writes the sequence alignment in the Clustal format to w,
+// in blocks of 60 characters followed by a conservation line. The
+// alignment must be valid, see Alignment.Valid.
+func (a Alignment) WriteClustal(w io.Writer) error {
+	if !a.Valid() {
+		return ErrInvalidAlignment
+	}
+	nameWidth := 0
+	for _, s := range a {
+		if len(s.ID()) == 0 || strings.IndexFunc(s.ID(), unicode.IsSpace) >= 0 {
+			return fmt.Errorf("invalid Clustal name %q", s.ID())
+		}
+		if n := utf8.RuneCountInString(s.ID()); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	nameWidth += 6
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("CLUSTAL W multiple sequence alignment\n\n")
+	if len(a) == 0 {
+		return bw.Flush()
+	}
+	seqs := make([][]rune, len(a))
+	for i, s := range a {
+		seqs[i] = []rune(s.Sequence())
+	}
+	conservation := []rune(ClustalConservation(a, "-"))
+	for start := 0; start < len(conservation); start += clustalBlockWidth {
+		end := start + clustalBlockWidth
+		if end > len(conservation) {
+			end = len(conservation)
+		}
+		bw.WriteByte('\n')
+		for i, s := range a {
+			fmt.Fprintf(bw, "%-*s%s\n", nameWidth, s.ID(), string(seqs[i][start:end]))
+		}
+		fmt.Fprintf(bw, "%-*s%s\n", nameWidth, "", string(conservation[start:end]))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,75 @@
+package gofasta
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadClustal(t *testing.T) {
+	r := strings.NewReader("CLUSTAL O(1.2.4) multiple sequence alignment\n" +
+		"\n\n" +
+		"seq1      ATGGCG 6\n" +
+		"seq2      ATG--G 4\n" +
+		"          ***  *\n" +
+		"\n" +
+		"seq1      TGG 9\n" +
+		"seq2      TGA 7\n" +
+		"          ** \n")
+	a, err := ReadClustal(r, false)
+	if err != nil {
+		t.Fatalf("ReadClustal: unexpected error %v", err)
+	}
+	if len(a) != 2 || a[0].ID() != "seq1" || a[0].Sequence() != "ATGGCGTGG" || a[1].Sequence() != "ATG--GTGA" {
+		t.Errorf("ReadClustal: unexpected alignment\n%s", a.ToFasta())
+	}
+}
+
+func TestReadClustal_Errors(t *testing.T) {
+	for _, data := range []string{
+		">seq1\nATG\n",
+		"CLUSTAL W\n\nseq1 ATG x\n",
+		"CLUSTAL W\n\nseq1\n",
+	} {
+		if _, err := ReadClustal(strings.NewReader(data), false); err == nil {
+			t.Errorf("ReadClustal: expected error for %#v", data)
+		}
+	}
+}
+
+func TestClustalConservation(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("a", "", "MSCNKL-F"),
+		NewCharSequence("b", "", "MTCNRLAF"),
+		NewCharSequence("c", "", "mAGDKLAY"),
+	}
+	exp := "*: ::* :"
+	if actual := ClustalConservation(a, "-"); exp != actual {
+		t.Errorf("ClustalConservation: expected %#v, actual %#v", exp, actual)
+	}
+}
+
+func TestAlignment_WriteClustal(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("seq1", "", strings.Repeat("ACGT", 20)),
+		NewCharSequence("s2", "", strings.Repeat("ACGA", 20)),
+	}
+	var buff bytes.Buffer
+	if err := a.WriteClustal(&buff); err != nil {
+		t.Fatalf("WriteClustal: unexpected error %v", err)
+	}
+	lines := strings.Split(buff.String(), "\n")
+	if exp := "seq1      " + strings.Repeat("ACGT", 15); lines[3] != exp {
+		t.Errorf("WriteClustal: expected %#v, actual %#v", exp, lines[3])
+	}
+	if exp := "          " + strings.Repeat("*** ", 15); lines[5] != exp {
+		t.Errorf("WriteClustal: expected %#v, actual %#v", exp, lines[5])
+	}
+	actual, err := ReadClustal(&buff, false)
+	if err != nil {
+		t.Fatalf("ReadClustal: unexpected error %v", err)
+	}
+	if a.ToFasta() != actual.ToFasta() {
+		t.Errorf("WriteClustal: round trip mismatch\n%s", actual.ToFasta())
+	}
+}
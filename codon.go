@@ -19,6 +19,7 @@ type CodonSequence struct {
 	CharSequence
 	prot   string
 	codons []string // TODO: Change to *string to avoid duplication
+	code   *GeneticCodeTable
 }
 
 // NewCodonSequence is a constructor that creates a new CodonSequence where
@@ -35,6 +36,19 @@ func NewCodonSequence(name, description, sequence string) *CodonSequence {
 	return s
 }
 
+// NewCodonSequenceChecked is like NewCodonSequence but returns an error
+// wrapping ErrCodonLength instead of panicking if the length of the
+// nucleotide sequence is not divisible by 3.
+func NewCodonSequenceChecked(name, description, sequence string) (*CodonSequence, error) {
+	s := new(CodonSequence)
+	s.name = name
+	s.description = description
+	if err := s.SetSequenceChecked(sequence); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // ID returns the name of CodonSequence.
 func (s *CodonSequence) ID() string {
 	return s.name
@@ -59,7 +73,7 @@ func (s *CodonSequence) Codons() []string {
 
 // Prot returns the naively translated protein sequence of the sequence.
 func (s *CodonSequence) Prot() string {
-	// The prot field contains the translated amino acid sequence based on the seq  field using the standard genetic code.
+	// The prot field contains the translated amino acid sequence based on the seq  field using its genetic code.
 	// The amino acid sequence is encoded as single-character amino acids and stored as a string.
 	return s.prot
 }
@@ -91,7 +105,8 @@ func (s *CodonSequence) Codon(i int) string {
 // SetSequence assigns a nucleotide sequence to the seq field of CodonSequence.
 // It also automatically fills the codons and prot fields by splitting the
 // nucleotide sequence into triplets and translating each codon into its
-// corresponding amino acid using the standard genetic code respectively.
+// corresponding amino acid using the genetic code of CodonSequence
+// respectively (see SetGeneticCode).
 func (s *CodonSequence) SetSequence(seq string) {
 	// Converts sequence to rune slice to deal with unicode chars
 	seqRune := []rune(seq)
@@ -107,21 +122,65 @@ func (s *CodonSequence) SetSequence(seq string) {
 	}
 	s.codons = codons
 	// Overwrites the value of .prot
-	s.prot = Translate(seq)
+	s.prot = s.translate(seq)
+}
+
+// SetSequenceChecked is like SetSequence but returns an error wrapping
+// ErrCodonLength instead of panicking if the length of the nucleotide
+// sequence is not divisible by 3. The CodonSequence is left unchanged if an
+// error is returned.
+func (s *CodonSequence) SetSequenceChecked(seq string) error {
+	if n := len([]rune(seq)); n%3 != 0 {
+		return fmt.Errorf("length %d: %w", n, ErrCodonLength)
+	}
+	s.SetSequence(seq)
+	return nil
 }
 
 // SetCodons assigns a nucleotide sequence delimited by codon to the codons
 // field of CodonSequence. It also automatically fills the seq and prot
 // fields by joining the codons into a single continuous string and
 // translating each codon into its corresponding amino acid using the
-// standard genetic code respectively.
+// genetic code of CodonSequence respectively.
 func (s *CodonSequence) SetCodons(seq []string) {
 	// Overwrites value of .codons
 	s.codons = seq
 	// Overwrite value of .sequence
 	s.sequence = strings.Join(seq, "")
 	// Overwrites the value of .prot
-	s.prot = Translate(s.sequence)
+	s.prot = s.translate(s.sequence)
+}
+
+// GeneticCode returns the translation table used to translate the codons
+// of CodonSequence. The standard genetic code is used unless another table
+// has been set using SetGeneticCode.
+func (s *CodonSequence) GeneticCode() *GeneticCodeTable {
+	if s.code == nil {
+		return StandardGeneticCode
+	}
+	return s.code
+}
+
+// SetGeneticCode sets the NCBI translation table used to translate the
+// codons of CodonSequence, such as 2 for the vertebrate mitochondrial code,
+// and retranslates the protein sequence.
+func (s *CodonSequence) SetGeneticCode(id int) error {
+	code, err := GeneticCodeByID(id)
+	if err != nil {
+		return err
+	}
+	s.code = code
+	s.prot = s.translate(s.sequence)
+	return nil
+}
+
+// translate translates the nucleotide sequence using the genetic code of
+// CodonSequence.
+func (s *CodonSequence) translate(seq string) string {
+	if s.code == nil {
+		return Translate(seq)
+	}
+	return s.code.Translate(seq)
 }
 
 // UngappedCoords returns the positions in the sequence where the character
@@ -1,6 +1,7 @@
 package gofasta
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -24,7 +25,7 @@ func TestCodonSequence_Properties(t *testing.T) {
 	seq := "ATGGCGTGG"
 	prot := "MAW"
 	codons := []string{"ATG", "GCG", "TGG"}
-	actual := CodonSequence{CharSequence{id, desc, seq}, prot, codons}
+	actual := CodonSequence{CharSequence: CharSequence{name: id, description: desc, sequence: seq}, prot: prot, codons: codons}
 
 	if id != actual.ID() {
 		t.Errorf("ID: expected %#v, actual %#v", id, actual.ID())
@@ -44,7 +45,7 @@ func TestCodonSequence_Properties(t *testing.T) {
 }
 
 func TestCodonSequence_ToUpper(t *testing.T) {
-	actual := CodonSequence{CharSequence{"a", "test", "atggcgtgg"}, "maw", []string{"atg", "gcg", "tgg"}}
+	actual := CodonSequence{CharSequence: CharSequence{name: "a", description: "test", sequence: "atggcgtgg"}, prot: "maw", codons: []string{"atg", "gcg", "tgg"}}
 	actual.ToUpper()
 	if exp := "ATGGCGTGG"; exp != actual.sequence {
 		t.Errorf("ToUpper: expected %#v, actual %#v", exp, actual.sequence)
@@ -57,7 +58,7 @@ func TestCodonSequence_ToUpper(t *testing.T) {
 	}
 }
 func TestCodonSequence_ToLower(t *testing.T) {
-	actual := CodonSequence{CharSequence{"a", "test", "ATGGCGTGG"}, "MAW", []string{"ATG", "GCG", "TGG"}}
+	actual := CodonSequence{CharSequence: CharSequence{name: "a", description: "test", sequence: "ATGGCGTGG"}, prot: "MAW", codons: []string{"ATG", "GCG", "TGG"}}
 	actual.ToLower()
 	if exp := "atggcgtgg"; exp != actual.sequence {
 		t.Errorf("ToUpper: expected %#v, actual %#v", exp, actual.sequence)
@@ -103,12 +104,12 @@ func TestCodonSequence_SetSequence_Error(t *testing.T) {
 			t.Errorf("SetSequence: expected panic, but did not panic")
 		}
 	}()
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	seq := "TTT---TTCTTATTGA"
 	s.SetSequence(seq)
 }
 func TestCodonSequence_SetSequence_seq(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	seq := "TTT---TTCTTATTG"
 	s.SetSequence(seq)
 
@@ -118,7 +119,7 @@ func TestCodonSequence_SetSequence_seq(t *testing.T) {
 }
 
 func TestCodonSequence_SetSequence_prot(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	seq := "TTTTTCTTATTGTCTTCCTCATCGTATTACTAATAGTGTTGCTGATGGCTTCTCCTACTGCCTCCCCCACCGCATCACCAACAGCGTCGCCGACGGATTATCATAATGACTACCACAACGAATAACAAAAAGAGTAGCAGAAGGGTTGTCGTAGTGGCTGCCGCAGCGGATGACGAAGAGGGTGGCGGAGGG---NNN"
 	s.SetSequence(seq)
 	exp := "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG-X"
@@ -129,7 +130,7 @@ func TestCodonSequence_SetSequence_prot(t *testing.T) {
 }
 
 func TestCodonSequence_SetSequence_codon(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	seq := "TTTTTCTTATTGTCTTCCTCATCGTATTACTAATAGTGTTGCTGATGGCTTCTCCTACTGCCTCCCCCACCGCATCACCAACAGCGTCGCCGACGGATTATCATAATGACTACCACAACGAATAACAAAAAGAGTAGCAGAAGGGTTGTCGTAGTGGCTGCCGCAGCGGATGACGAAGAGGGTGGCGGAGGG---NNN"
 	s.SetSequence(seq)
 	exp := []string{
@@ -160,7 +161,7 @@ func TestCodonSequence_SetSequence_codon(t *testing.T) {
 }
 
 func TestCodonSequence_SetCodons_seq(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	codons := []string{
 		"TTT", "TTC", "TTA", "TTG",
 		"TCT", "TCC", "TCA", "TCG",
@@ -189,7 +190,7 @@ func TestCodonSequence_SetCodons_seq(t *testing.T) {
 }
 
 func TestCodonSequence_SetCodons_prot(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	codons := []string{
 		"TTT", "TTC", "TTA", "TTG",
 		"TCT", "TCC", "TCA", "TCG",
@@ -218,7 +219,7 @@ func TestCodonSequence_SetCodons_prot(t *testing.T) {
 }
 
 func TestCodonSequence_SetCodons_codon(t *testing.T) {
-	s := CodonSequence{CharSequence{"test", "", ""}, "", []string{}}
+	s := CodonSequence{CharSequence: CharSequence{name: "test", description: "", sequence: ""}, prot: "", codons: []string{}}
 	codons := []string{
 		"TTT", "TTC", "TTA", "TTG",
 		"TCT", "TCC", "TCA", "TCG",
@@ -304,3 +305,33 @@ func TestCodonSequence_UngappedPositionSlice_Error(t *testing.T) {
 	s := NewCodonSequence("test", "", seq)
 	s.UngappedPositionSlice("--")
 }
+
+func TestNewCodonSequenceChecked(t *testing.T) {
+	s, err := NewCodonSequenceChecked("a", "test", "ATGGCGTGG")
+	if err != nil {
+		t.Fatalf("NewCodonSequenceChecked: unexpected error %v", err)
+	}
+	if exp := "MAW"; exp != s.Prot() {
+		t.Errorf("NewCodonSequenceChecked: expected %#v, actual %#v", exp, s.Prot())
+	}
+	_, err = NewCodonSequenceChecked("a", "test", "ATGGCGTG")
+	if !errors.Is(err, ErrCodonLength) {
+		t.Errorf("NewCodonSequenceChecked: expected ErrCodonLength, actual %v", err)
+	}
+}
+
+func TestCodonSequence_SetSequenceChecked(t *testing.T) {
+	s := NewCodonSequence("a", "test", "ATGGCGTGG")
+	if err := s.SetSequenceChecked("ATGGC"); !errors.Is(err, ErrCodonLength) {
+		t.Errorf("SetSequenceChecked: expected ErrCodonLength, actual %v", err)
+	}
+	if exp := "ATGGCGTGG"; exp != s.Sequence() {
+		t.Errorf("SetSequenceChecked: expected unchanged sequence %#v, actual %#v", exp, s.Sequence())
+	}
+	if err := s.SetSequenceChecked("TGG"); err != nil {
+		t.Errorf("SetSequenceChecked: unexpected error %v", err)
+	}
+	if exp := "W"; exp != s.Prot() {
+		t.Errorf("SetSequenceChecked: expected %#v, actual %#v", exp, s.Prot())
+	}
+}
@@ -0,0 +1,307 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CodonUsageTable holds codon counts under a genetic code. Name identifies
+// the table in TSV output, such as a genome or gene name. Counts only
+// contains the 64 unambiguous codons; gaps and codons with ambiguous bases
+// are not counted.
+type CodonUsageTable struct {
+	Name   string
+	Code   *GeneticCodeTable
+	Counts CodonUsage
+}
+
+// NewCodonUsageTable creates an empty codon usage table using the NCBI
+// translation table with the given ID, or the standard genetic code if
+// code is 0.
+func NewCodonUsageTable(name string, code int) (*CodonUsageTable, error) {
+	table := StandardGeneticCode
+	if code != 0 {
+		var err error
+		if table, err = GeneticCodeByID(code); err != nil {
+			return nil, err
+		}
+	}
+	return &CodonUsageTable{Name: name, Code: table, Counts: make(CodonUsage)}, nil
+}
+
+// CountCodons counts the codons of all sequences in the alignment. See
+// NewCodonUsageTable and Add.
+func CountCodons(name string, a Alignment, code int) (*CodonUsageTable, error) {
+	t, err := NewCodonUsageTable(name, code)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range a {
+		if err := t.Add(s); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Add counts the codons of a sequence. Codons of a CodonSequence are used
+// as is, other sequences are split into codons and must have a length
+// divisible by 3. Lowercase and RNA codons are counted as uppercase DNA.
+func (t *CodonUsageTable) Add(s Sequence) error {
+	codons, err := codonsOf(s)
+	if err != nil {
+		return err
+	}
+	for _, codon := range codons {
+		codon = normalizeCodon(codon)
+		if codonIndex(codon) >= 0 {
+			t.Counts[codon]++
+		}
+	}
+	return nil
+}
+
+// codonsOf returns the codons of a sequence. Codons of a CodonSequence are
+// used as is, other sequences are split into codons and must have a length
+// divisible by 3.
+func codonsOf(s Sequence) ([]string, error) {
+	if c, ok := s.(*CodonSequence); ok {
+		return c.Codons(), nil
+	}
+	seq := s.Sequence()
+	if len(seq)%3 != 0 {
+		return nil, fmt.Errorf("%s: length %d: %w", s.ID(), len(seq), ErrCodonLength)
+	}
+	codons := make([]string, 0, len(seq)/3)
+	for i := 0; i < len(seq); i += 3 {
+		codons = append(codons, seq[i:i+3])
+	}
+	return codons, nil
+}
+
+// Total returns the number of counted codons.
+func (t *CodonUsageTable) Total() (total float64) {
+	for _, n := range t.Counts {
+		total += n
+	}
+	return
+}
+
+// families groups the codons by the amino acid they encode, in the order of
+// Codons. Stop codons are grouped under '*'.
+func (t *CodonUsageTable) families() map[byte][]string {
+	families := make(map[byte][]string)
+	for i, c := range Codons {
+		aa := t.Code.AminoAcids[i]
+		families[aa] = append(families[aa], c)
+	}
+	return families
+}
+
+// RSCU returns the relative synonymous codon usage of each codon, that is
+// its count divided by the mean count of the codons encoding the same amino
+// acid. Codons of amino acids that were not observed have an RSCU of 0.
+func (t *CodonUsageTable) RSCU() CodonUsage {
+	rscu := make(CodonUsage)
+	for _, codons := range t.families() {
+		sum := 0.0
+		for _, c := range codons {
+			sum += t.Counts[c]
+		}
+		for _, c := range codons {
+			if sum > 0 {
+				rscu[c] = t.Counts[c] * float64(len(codons)) / sum
+			} else {
+				rscu[c] = 0
+			}
+		}
+	}
+	return rscu
+}
+
+// RelativeAdaptiveness returns the relative adaptiveness w of each codon,
+// that is its count divided by the count of the most used codon encoding the
+// same amino acid. Following Sharp and Li (1987), a count of 0.5 is used for
+// codons that were not observed so that w is never 0. Codons of amino acids
+// that were not observed have a w of 0.
+func (t *CodonUsageTable) RelativeAdaptiveness() CodonUsage {
+	w := make(CodonUsage)
+	for _, codons := range t.families() {
+		max := 0.0
+		for _, c := range codons {
+			max = math.Max(max, t.Counts[c])
+		}
+		for _, c := range codons {
+			switch {
+			case max == 0:
+				w[c] = 0
+			case t.Counts[c] == 0:
+				w[c] = 0.5 / max
+			default:
+				w[c] = t.Counts[c] / max
+			}
+		}
+	}
+	return w
+}
+
+// CAI returns the Codon Adaptation Index of a sequence using the table as
+// the reference set, such as the codon usage of highly expressed genes.
+// The CAI is the geometric mean of the relative adaptiveness of the codons
+// of the sequence. Stop codons, codons of amino acids encoded by a single
+// codon, such as ATG and TGG in the standard code, gaps and ambiguous
+// codons are skipped.
+func (t *CodonUsageTable) CAI(s Sequence) (float64, error) {
+	query := &CodonUsageTable{Code: t.Code, Counts: make(CodonUsage)}
+	if err := query.Add(s); err != nil {
+		return 0, err
+	}
+	w := t.RelativeAdaptiveness()
+	families := t.families()
+	logSum, n := 0.0, 0.0
+	for i, c := range Codons {
+		aa := t.Code.AminoAcids[i]
+		if aa == '*' || len(families[aa]) == 1 || query.Counts[c] == 0 {
+			continue
+		}
+		if w[c] == 0 {
+			return 0, fmt.Errorf("%s: amino acid %c is absent from the reference set", s.ID(), aa)
+		}
+		logSum += query.Counts[c] * math.Log(w[c])
+		n += query.Counts[c]
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%s: no codons to compute CAI", s.ID())
+	}
+	return math.Exp(logSum / n), nil
+}
+
+// ENC returns the effective number of codons (Wright 1990), ranging from
+// 20 when a single codon is used per amino acid to the number of sense
+// codons when all synonymous codons are used equally. Amino acids are
+// grouped by the size of their codon family under the genetic code, and the
+// average homozygosity F of each group is computed from the amino acids
+// observed at least twice. If no amino acid of the 3-fold group was
+// observed, its F is the mean of the 2- and 4-fold groups. Groups without
+// any observation are assumed to be used equally. The result is capped at
+// the number of sense codons.
+func (t *CodonUsageTable) ENC() float64 {
+	type group struct {
+		members int
+		sumF    float64
+		nF      int
+	}
+	groups := make(map[int]*group)
+	senseCodons := 0
+	for aa, codons := range t.families() {
+		if aa == '*' {
+			continue
+		}
+		k := len(codons)
+		senseCodons += k
+		g, ok := groups[k]
+		if !ok {
+			g = new(group)
+			groups[k] = g
+		}
+		g.members++
+		n, sumSq := 0.0, 0.0
+		for _, c := range codons {
+			n += t.Counts[c]
+		}
+		if k == 1 || n < 2 {
+			continue
+		}
+		for _, c := range codons {
+			p := t.Counts[c] / n
+			sumSq += p * p
+		}
+		g.sumF += (n*sumSq - 1) / (n - 1)
+		g.nF++
+	}
+	meanF := func(k int) (float64, bool) {
+		g, ok := groups[k]
+		if !ok || g.nF == 0 || g.sumF == 0 {
+			return 0, false
+		}
+		return g.sumF / float64(g.nF), true
+	}
+	enc := 0.0
+	for k, g := range groups {
+		if k == 1 {
+			enc += float64(g.members)
+			continue
+		}
+		f, ok := meanF(k)
+		if !ok && k == 3 {
+			f2, ok2 := meanF(2)
+			f4, ok4 := meanF(4)
+			f, ok = (f2+f4)/2, ok2 && ok4
+		}
+		if ok {
+			enc += float64(g.members) / f
+		} else {
+			enc += float64(g.members * k)
+		}
+	}
+	return math.Min(enc, float64(senseCodons))
+}
+
+// GC3 returns the fraction of G and C at the third position of the counted
+// sense codons. It returns NaN if no sense codon was counted.
+func (t *CodonUsageTable) GC3() float64 {
+	gc, n := 0.0, 0.0
+	for i, c := range Codons {
+		if t.Code.AminoAcids[i] == '*' {
+			continue
+		}
+		n += t.Counts[c]
+		if c[2] == 'G' || c[2] == 'C' {
+			gc += t.Counts[c]
+		}
+	}
+	return gc / n
+}
+
+// WriteTSV writes the table to w as tab-separated values. See
+// WriteCodonUsageTSV.
+func (t *CodonUsageTable) WriteTSV(w io.Writer) error {
+	return WriteCodonUsageTSV(w, t)
+}
+
+// WriteCodonUsageTSV writes codon usage tables to w as tab-separated values
+// with a header row. Each table contributes one row per codon, in the order
+// of Codons, with the columns name, codon, aa, count, per_thousand, rscu and
+// w, so that tables of many genomes can be compared in a single file.
+func WriteCodonUsageTSV(w io.Writer, tables ...*CodonUsageTable) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("name\tcodon\taa\tcount\tper_thousand\trscu\tw\n")
+	for _, t := range tables {
+		total := t.Total()
+		rscu := t.RSCU()
+		adaptiveness := t.RelativeAdaptiveness()
+		for i, c := range Codons {
+			perThousand := 0.0
+			if total > 0 {
+				perThousand = 1000 * t.Counts[c] / total
+			}
+			fmt.Fprintf(bw, "%s\t%s\t%c\t%g\t%.2f\t%.3f\t%.3f\n",
+				t.Name, c, t.Code.AminoAcids[i], t.Counts[c], perThousand, rscu[c], adaptiveness[c])
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteCodonUsageSummaryTSV writes one row per codon usage table to w as
+// tab-separated values with the columns name, code, codons, gc3 and enc.
+// Like DNDSMatrix.WriteTSV, an undefined GC3 is written as NA.
+func WriteCodonUsageSummaryTSV(w io.Writer, tables ...*CodonUsageTable) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("name\tcode\tcodons\tgc3\tenc\n")
+	for _, t := range tables {
+		fmt.Fprintf(bw, "%s\t%d\t%g\t%s\t%.2f\n", t.Name, t.Code.ID, t.Total(), formatEstimate(t.GC3()), t.ENC())
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,123 @@
+package gofasta
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func codonUsageTestTable(t *testing.T) *CodonUsageTable {
+	a := Alignment{
+		NewCodonSequence("a", "", "ATGGCTGCTGCCTTATAA"),
+		NewCharSequence("b", "", "atggcugcgctg---NNN"),
+	}
+	table, err := CountCodons("test", a, 0)
+	if err != nil {
+		t.Fatalf("CountCodons: unexpected error %v", err)
+	}
+	return table
+}
+
+func TestCountCodons(t *testing.T) {
+	table := codonUsageTestTable(t)
+	exp := CodonUsage{"ATG": 2, "GCT": 3, "GCC": 1, "GCG": 1, "TTA": 1, "CTG": 1, "TAA": 1}
+	for c, n := range exp {
+		if table.Counts[c] != n {
+			t.Errorf("CountCodons: expected %s %g, actual %g", c, n, table.Counts[c])
+		}
+	}
+	if table.Total() != 10 {
+		t.Errorf("Total: expected %d, actual %g", 10, table.Total())
+	}
+	if _, err := CountCodons("x", Alignment{NewCharSequence("c", "", "ATGA")}, 0); err == nil {
+		t.Errorf("CountCodons: expected error for incomplete codon")
+	}
+}
+
+func TestCodonUsageTable_RSCU(t *testing.T) {
+	table := codonUsageTestTable(t)
+	rscu := table.RSCU()
+	cases := map[string]float64{"GCT": 2.4, "GCC": 0.8, "GCA": 0, "ATG": 1, "TTA": 3, "CTT": 0, "TGG": 0}
+	for c, exp := range cases {
+		if math.Abs(rscu[c]-exp) > 1e-9 {
+			t.Errorf("RSCU: expected %s %g, actual %g", c, exp, rscu[c])
+		}
+	}
+	w := table.RelativeAdaptiveness()
+	if math.Abs(w["GCC"]-1.0/3) > 1e-9 || math.Abs(w["GCA"]-0.5/3) > 1e-9 {
+		t.Errorf("RelativeAdaptiveness: unexpected values %g %g", w["GCC"], w["GCA"])
+	}
+}
+
+func TestCodonUsageTable_CAI(t *testing.T) {
+	table := codonUsageTestTable(t)
+	cai, err := table.CAI(NewCharSequence("q", "", "ATGGCTGCC"))
+	if err != nil {
+		t.Fatalf("CAI: unexpected error %v", err)
+	}
+	if exp := math.Sqrt(1.0 / 3); math.Abs(cai-exp) > 1e-9 {
+		t.Errorf("CAI: expected %g, actual %g", exp, cai)
+	}
+	if _, err := table.CAI(NewCharSequence("q", "", "AAA")); err == nil {
+		t.Errorf("CAI: expected error for amino acid absent from reference")
+	}
+	if _, err := table.CAI(NewCharSequence("q", "", "ATGTGG")); err == nil {
+		t.Errorf("CAI: expected error for sequence without informative codons")
+	}
+}
+
+func TestCodonUsageTable_ENC_GC3(t *testing.T) {
+	uniform, _ := NewCodonUsageTable("uniform", 0)
+	biased, _ := NewCodonUsageTable("biased", 0)
+	families := uniform.families()
+	for aa, codons := range families {
+		if aa == '*' {
+			continue
+		}
+		for _, c := range codons {
+			uniform.Counts[c] = 100
+		}
+		biased.Counts[codons[0]] = 100
+	}
+	if enc := uniform.ENC(); math.Abs(enc-61) > 1 {
+		t.Errorf("ENC: expected about %d, actual %g", 61, enc)
+	}
+	if enc := biased.ENC(); math.Abs(enc-20) > 1e-9 {
+		t.Errorf("ENC: expected %d, actual %g", 20, enc)
+	}
+	table := codonUsageTestTable(t)
+	if exp := 5.0 / 9; math.Abs(table.GC3()-exp) > 1e-9 {
+		t.Errorf("GC3: expected %g, actual %g", exp, table.GC3())
+	}
+}
+
+func TestWriteCodonUsageTSV(t *testing.T) {
+	table := codonUsageTestTable(t)
+	var buff bytes.Buffer
+	if err := table.WriteTSV(&buff); err != nil {
+		t.Fatalf("WriteTSV: unexpected error %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buff.String(), "\n"), "\n")
+	if len(lines) != 65 {
+		t.Fatalf("WriteTSV: expected %d lines, actual %d", 65, len(lines))
+	}
+	if exp := "test\tGCT\tA\t3\t300.00\t2.400\t1.000"; !strings.Contains(buff.String(), exp) {
+		t.Errorf("WriteTSV: expected line %#v in %#v", exp, buff.String())
+	}
+	buff.Reset()
+	if err := WriteCodonUsageSummaryTSV(&buff, table); err != nil {
+		t.Fatalf("WriteCodonUsageSummaryTSV: unexpected error %v", err)
+	}
+	if exp := "name\tcode\tcodons\tgc3\tenc\ntest\t1\t10\t0.5556\t"; !strings.HasPrefix(buff.String(), exp) {
+		t.Errorf("WriteCodonUsageSummaryTSV: expected prefix %#v, actual %#v", exp, buff.String())
+	}
+	empty, _ := NewCodonUsageTable("empty", 0)
+	buff.Reset()
+	if err := WriteCodonUsageSummaryTSV(&buff, empty); err != nil {
+		t.Fatalf("WriteCodonUsageSummaryTSV: unexpected error %v", err)
+	}
+	if exp := "empty\t1\t0\tNA\t"; !strings.Contains(buff.String(), exp) {
+		t.Errorf("WriteCodonUsageSummaryTSV: expected %#v, actual %#v", exp, buff.String())
+	}
+}
@@ -0,0 +1,274 @@
+package gofasta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Compression identifies a compression codec used for sequence files.
+type Compression int
+
+// Supported compression codecs.
+// BGZF is the blocked gzip format used by samtools and tabix. BGZF files are
+// valid gzip files that can also be indexed for random access.
+// Bzip2 can only be read, since the standard library has no bzip2 encoder.
+const (
+	NoCompression Compression = iota
+	Gzip
+	BGZF
+	Bzip2
+)
+
+var compressionNames = map[Compression]string{
+	NoCompression: "none",
+	Gzip:          "gzip",
+	BGZF:          "bgzf",
+	Bzip2:         "bzip2",
+}
+
+func (c Compression) String() string {
+	if name, ok := compressionNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Compression(%d)", int(c))
+}
+
+// ErrUnsupportedCompression is returned when a compression codec is
+// recognized but cannot be handled: when reading Zstandard input, which is
+// detected from its magic bytes so that it is not mistaken for plain text,
+// and when writing bzip2 output.
+var ErrUnsupportedCompression = errors.New("unsupported compression")
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// DetectCompression identifies the compression codec of the stream read by
+// r from its magic bytes without consuming any input. Zstandard input is
+// reported as an error wrapping ErrUnsupportedCompression.
+func DetectCompression(r *bufio.Reader) (Compression, error) {
+	// gzip header with FEXTRA flag set and a BC subfield is BGZF
+	header, err := r.Peek(18)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return NoCompression, err
+	}
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		if len(header) >= 16 && header[3]&0x04 != 0 && header[12] == 'B' && header[13] == 'C' {
+			return BGZF, nil
+		}
+		return Gzip, nil
+	case bytes.HasPrefix(header, bzip2Magic):
+		return Bzip2, nil
+	case bytes.HasPrefix(header, zstdMagic):
+		return NoCompression, fmt.Errorf("zstd: %w", ErrUnsupportedCompression)
+	}
+	return NoCompression, nil
+}
+
+// NewDecompressReader returns a reader that transparently decompresses the
+// stream read from r. The compression codec is detected from the magic
+// bytes at the start of the stream, and uncompressed input is passed
+// through unchanged. Closing the returned reader does not close r.
+func NewDecompressReader(r io.Reader) (io.ReadCloser, Compression, error) {
+	br := bufio.NewReader(r)
+	c, err := DetectCompression(br)
+	if err != nil {
+		return nil, c, err
+	}
+	switch c {
+	case Gzip, BGZF:
+		// gzip.Reader reads concatenated members, which covers BGZF blocks
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, c, err
+		}
+		return gr, c, nil
+	case Bzip2:
+		return io.NopCloser(bzip2.NewReader(br)), c, nil
+	}
+	return io.NopCloser(br), c, nil
+}
+
+// OpenFile opens a file for reading and transparently decompresses its
+// contents. Closing the returned reader also closes the file.
+func OpenFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := NewDecompressReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &fileReadCloser{r, f}, nil
+}
+
+type fileReadCloser struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r *fileReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	if ferr := r.file.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// NewCompressWriter returns a writer that compresses data written to it
+// using codec c before writing it to w. NoCompression, Gzip and BGZF are
+// supported. The returned writer must be closed to flush all data, but
+// closing it does not close w.
+func NewCompressWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case NoCompression:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case BGZF:
+		return NewBGZFWriter(w), nil
+	}
+	return nil, fmt.Errorf("writing %s: %w", c, ErrUnsupportedCompression)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// bgzfBlockDataSize is the maximum amount of uncompressed data stored in a
+// single BGZF block. This is the same value used by htslib.
+const bgzfBlockDataSize = 0xff00
+
+// bgzfMinBlockSize is the size of a BGZF block without compressed data,
+// that is the 18-byte header and the 8-byte CRC32 and ISIZE trailer.
+const bgzfMinBlockSize = 26
+
+// bgzfMaxBlockSize is the maximum size of a compressed BGZF block.
+const bgzfMaxBlockSize = 0x10000
+
+// bgzfEOF is the empty block that marks the end of a BGZF file.
+var bgzfEOF = []byte{
+	0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0xff, 0x06, 0x00, 0x42, 0x43, 0x02, 0x00,
+	0x1b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00,
+}
+
+// BGZFWriter compresses data into BGZF blocks. Each block holds at most
+// 65280 bytes of uncompressed data and is a complete gzip member, which
+// allows the output to be indexed.
+type BGZFWriter struct {
+	w      io.Writer
+	buf    []byte
+	cbuf   bytes.Buffer
+	closed bool
+}
+
+// NewBGZFWriter creates a new BGZFWriter that writes compressed blocks to w.
+func NewBGZFWriter(w io.Writer) *BGZFWriter {
+	return &BGZFWriter{
+		w:   w,
+		buf: make([]byte, 0, bgzfBlockDataSize),
+	}
+}
+
+// Write buffers p and writes full blocks to the underlying io.Writer.
+func (w *BGZFWriter) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, errors.New("bgzf: write to closed writer")
+	}
+	for len(p) > 0 {
+		m := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		n += m
+		p = p[m:]
+		if len(w.buf) == cap(w.buf) {
+			if err = w.Flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush compresses any buffered data into a block and writes it to the
+// underlying io.Writer.
+func (w *BGZFWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	if err := w.writeBlock(flate.DefaultCompression); err != nil {
+		return err
+	}
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close flushes buffered data and writes the BGZF end-of-file marker.
+func (w *BGZFWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+	_, err := w.w.Write(bgzfEOF)
+	return err
+}
+
+func (w *BGZFWriter) writeBlock(level int) error {
+	w.cbuf.Reset()
+	fw, err := flate.NewWriter(&w.cbuf, level)
+	if err != nil {
+		return err
+	}
+	fw.Write(w.buf)
+	if err = fw.Close(); err != nil {
+		return err
+	}
+	blockSize := 18 + w.cbuf.Len() + 8
+	if blockSize > bgzfMaxBlockSize {
+		// Incompressible data expands slightly when deflated. Stored blocks
+		// always fit because of the limit on uncompressed data per block.
+		if level == flate.NoCompression {
+			return errors.New("bgzf: block too large")
+		}
+		return w.writeBlock(flate.NoCompression)
+	}
+
+	header := []byte{
+		0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0xff, 0x06, 0x00, 'B', 'C', 0x02, 0x00,
+		0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint16(header[16:], uint16(blockSize-1))
+	trailer := make([]byte, 8)
+	binary.LittleEndian.PutUint32(trailer, crc32.ChecksumIEEE(w.buf))
+	binary.LittleEndian.PutUint32(trailer[4:], uint32(len(w.buf)))
+
+	for _, b := range [][]byte{header, w.cbuf.Bytes(), trailer} {
+		if _, err := w.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package gofasta
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// bzip2 compressed ">a\nACGT\n"
+var bzip2Fasta = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x27, 0x0b,
+	0x89, 0x03, 0x00, 0x00, 0x01, 0x4f, 0x00, 0x00, 0x10, 0x00, 0x01, 0x28,
+	0x80, 0x04, 0x00, 0x20, 0x00, 0x20, 0x00, 0x31, 0x0c, 0x01, 0x06, 0x99,
+	0xa4, 0x16, 0x38, 0x14, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x40, 0x9c, 0x2e,
+	0x24, 0x0c,
+}
+
+func compressBytes(t *testing.T, data []byte, c Compression) []byte {
+	var buff bytes.Buffer
+	w, err := NewCompressWriter(&buff, c)
+	if err != nil {
+		t.Fatalf("NewCompressWriter(%s): unexpected error %v", c, err)
+	}
+	if _, err = w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buff.Bytes()
+}
+
+func TestNewDecompressReader(t *testing.T) {
+	data := []byte(">a\nACGT\n")
+	for _, c := range []Compression{NoCompression, Gzip, BGZF} {
+		r, actualC, err := NewDecompressReader(bytes.NewReader(compressBytes(t, data, c)))
+		if err != nil {
+			t.Fatalf("NewDecompressReader(%s): unexpected error %v", c, err)
+		}
+		if actualC != c {
+			t.Errorf("NewDecompressReader: expected %s, actual %s", c, actualC)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("NewDecompressReader(%s): unexpected error %v", c, err)
+		}
+		if !bytes.Equal(data, b) {
+			t.Errorf("NewDecompressReader(%s): expected %#v, actual %#v", c, string(data), string(b))
+		}
+	}
+}
+
+func TestNewDecompressReader_Bzip2(t *testing.T) {
+	r, c, err := NewDecompressReader(bytes.NewReader(bzip2Fasta))
+	if err != nil || c != Bzip2 {
+		t.Fatalf("NewDecompressReader: expected bzip2, actual %s, %v", c, err)
+	}
+	b, _ := io.ReadAll(r)
+	if exp := ">a\nACGT\n"; exp != string(b) {
+		t.Errorf("NewDecompressReader: expected %#v, actual %#v", exp, string(b))
+	}
+}
+
+func TestNewDecompressReader_Zstd(t *testing.T) {
+	_, _, err := NewDecompressReader(bytes.NewReader([]byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}))
+	if !errors.Is(err, ErrUnsupportedCompression) {
+		t.Errorf("NewDecompressReader: expected ErrUnsupportedCompression, actual %v", err)
+	}
+}
+
+func TestDetectCompression_Short(t *testing.T) {
+	c, err := DetectCompression(bufio.NewReader(bytes.NewReader([]byte(">"))))
+	if err != nil || c != NoCompression {
+		t.Errorf("DetectCompression: expected none, actual %s, %v", c, err)
+	}
+}
+
+func TestBGZFWriter_Blocks(t *testing.T) {
+	data := make([]byte, 3*bgzfBlockDataSize+10)
+	rand.New(rand.NewSource(1)).Read(data)
+	b := compressBytes(t, data, BGZF)
+
+	// Walk the blocks using the BSIZE field of each header
+	blocks := 0
+	for off := 0; off < len(b); blocks++ {
+		if b[off] != 0x1f || b[off+1] != 0x8b || b[off+12] != 'B' || b[off+13] != 'C' {
+			t.Fatalf("BGZFWriter: invalid block header at offset %d", off)
+		}
+		off += int(b[off+16]) | int(b[off+17])<<8 + 1
+	}
+	// 4 data blocks and the EOF marker
+	if blocks != 5 {
+		t.Errorf("BGZFWriter: expected 5 blocks, actual %d", blocks)
+	}
+	if !bytes.HasSuffix(b, bgzfEOF) {
+		t.Errorf("BGZFWriter: expected EOF marker at end of output")
+	}
+	r, _, err := NewDecompressReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(data, actual) {
+		t.Errorf("BGZFWriter: round trip failed, %v", err)
+	}
+}
+
+func TestNewCompressWriter_Unsupported(t *testing.T) {
+	for _, c := range []Compression{Bzip2, Compression(99)} {
+		if _, err := NewCompressWriter(io.Discard, c); !errors.Is(err, ErrUnsupportedCompression) {
+			t.Errorf("NewCompressWriter(%s): expected ErrUnsupportedCompression, actual %v", c, err)
+		}
+	}
+}
+
+func TestAlignment_WriteCompressedFastaFile(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("test1", "test", "TTT---TTCTTATTG"),
+		NewCharSequence("test2", "", "TTT---TTCTTTTTG"),
+	}
+	for _, c := range []Compression{Gzip, BGZF} {
+		path := filepath.Join(t.TempDir(), "test.fa.gz")
+		if err := a.WriteCompressedFastaFile(path, c); err != nil {
+			t.Fatalf("WriteCompressedFastaFile(%s): unexpected error %v", c, err)
+		}
+		actual, err := ReadFastaFile(path, false)
+		if err != nil {
+			t.Fatalf("ReadFastaFile(%s): unexpected error %v", c, err)
+		}
+		if exp := a.ToFasta(); exp != actual.ToFasta() {
+			t.Errorf("ReadFastaFile(%s): expected %#v, actual %#v", c, exp, actual.ToFasta())
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package gofasta
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrNoComparableCodons is returned by DNDS when two sequences do not share
+// any codon pair that can be compared.
+var ErrNoComparableCodons = errors.New("no comparable codons")
+
+// DNDSMethod selects how synonymous and nonsynonymous sites and
+// differences are counted.
+type DNDSMethod int
+
+const (
+	// NeiGojobori counts sites and differences assuming equal rates for
+	// all nucleotide changes (Nei and Gojobori 1986). Pathways between
+	// codons are weighted equally.
+	NeiGojobori DNDSMethod = iota
+	// ModifiedNeiGojobori weights transitions by the
+	// transition/transversion rate ratio kappa when counting sites (Zhang,
+	// Rosenberg and Kumar 1998).
+	ModifiedNeiGojobori
+	// YangNielsen weights changes by kappa and by the F3x4 codon
+	// frequencies of the two sequences, both when counting sites and when
+	// weighting the pathways between codons (Yang and Nielsen 2000). Kappa
+	// and the codon frequencies are estimated once instead of iteratively
+	// as in YN00. A pseudocount of one is added to each base at each codon
+	// position, so that bases that are not observed still contribute
+	// sites.
+	YangNielsen
+)
+
+// DNDSOptions configures DNDS.
+//
+// Code is the NCBI translation table ID; 0 selects the standard genetic
+// code. Kappa is the transition/transversion rate ratio used by the
+// ModifiedNeiGojobori and YangNielsen methods. If Kappa is 0, it is
+// estimated from the compared codons using the Kimura 2-parameter model,
+// or set to 1 if it cannot be estimated.
+type DNDSOptions struct {
+	Method DNDSMethod
+	Code   int
+	Kappa  float64
+}
+
+// DNDSResult holds the outcome of a pairwise dN/dS estimation. S and N are
+// the numbers of synonymous and nonsynonymous sites, Sd and Nd the numbers
+// of synonymous and nonsynonymous differences, and PS and PN their
+// proportions. DS and DN are the Jukes-Cantor corrected numbers of
+// substitutions per site, which are NaN if the proportions are too large to
+// be corrected. Omega is DN/DS, which is NaN if DN and DS are both 0 and
+// +Inf if only DS is 0. Codons is the number of compared codons and Kappa
+// the transition/transversion rate ratio that was used.
+type DNDSResult struct {
+	S, N   float64
+	Sd, Nd float64
+	PS, PN float64
+	DS, DN float64
+	Omega  float64
+	Codons int
+	Kappa  float64
+}
+
+// isTransition returns true if the change between two nucleotides is a
+// transition (A<->G or C<->T).
+func isTransition(a, b byte) bool {
+	purine := func(c byte) bool { return c == 'A' || c == 'G' }
+	return a != b && purine(a) == purine(b)
+}
+
+// dndsModel holds the parameters used to weight nucleotide changes.
+type dndsModel struct {
+	code     *GeneticCodeTable
+	method   DNDSMethod
+	kappa    float64
+	baseFreq [3]map[byte]float64
+}
+
+// freq returns the equilibrium frequency of a codon. Codon frequencies are
+// only used by the YangNielsen method.
+func (m *dndsModel) freq(codon string) float64 {
+	if m.method != YangNielsen {
+		return 1
+	}
+	return m.baseFreq[0][codon[0]] * m.baseFreq[1][codon[1]] * m.baseFreq[2][codon[2]]
+}
+
+// rate returns the relative rate of the change from one codon to a
+// neighboring codon that differs at position pos.
+func (m *dndsModel) rate(from, to string, pos int) float64 {
+	r := 1.0
+	if m.method != NeiGojobori && isTransition(from[pos], to[pos]) {
+		r = m.kappa
+	}
+	return r * m.freq(to)
+}
+
+// sites returns the numbers of synonymous and nonsynonymous sites of a
+// codon. At each position, the synonymous fraction is the weighted share
+// of changes that do not alter the amino acid. Changes to stop codons are
+// counted as nonsynonymous, except by the YangNielsen method, which
+// ignores them.
+func (m *dndsModel) sites(codon string) (s, n float64) {
+	aa := m.code.TranslateCodon(codon)
+	for pos := 0; pos < 3; pos++ {
+		syn, total := 0.0, 0.0
+		for _, b := range []byte("TCAG") {
+			if b == codon[pos] {
+				continue
+			}
+			mutant := []byte(codon)
+			mutant[pos] = b
+			neighbor := string(mutant)
+			mutantAA := m.code.TranslateCodon(neighbor)
+			if mutantAA == "*" && m.method == YangNielsen {
+				continue
+			}
+			w := m.rate(codon, neighbor, pos)
+			total += w
+			if mutantAA == aa {
+				syn += w
+			}
+		}
+		if total > 0 {
+			s += syn / total
+			n += 1 - syn/total
+		}
+	}
+	return
+}
+
+// differences returns the numbers of synonymous and nonsynonymous
+// differences between two sense codons, averaged over all pathways of
+// single-nucleotide changes that do not pass through a stop codon. Pathways
+// are weighted equally, except by the YangNielsen method, which weights
+// them by the rates of their changes. ok is false if every pathway passes
+// through a stop codon.
+func (m *dndsModel) differences(c1, c2 string) (sd, nd float64, ok bool) {
+	var positions []int
+	for pos := 0; pos < 3; pos++ {
+		if c1[pos] != c2[pos] {
+			positions = append(positions, pos)
+		}
+	}
+	if len(positions) == 0 {
+		return 0, 0, true
+	}
+	totalWeight := 0.0
+	for _, path := range permutations(positions) {
+		current := []byte(c1)
+		weight := 1.0
+		pathSyn, pathNon := 0.0, 0.0
+		valid := true
+		for _, pos := range path {
+			from := string(current)
+			current[pos] = c2[pos]
+			to := string(current)
+			toAA := m.code.TranslateCodon(to)
+			if toAA == "*" {
+				valid = false
+				break
+			}
+			if m.method == YangNielsen {
+				weight *= m.rate(from, to, pos)
+			}
+			if toAA == m.code.TranslateCodon(from) {
+				pathSyn++
+			} else {
+				pathNon++
+			}
+		}
+		if !valid {
+			continue
+		}
+		sd += weight * pathSyn
+		nd += weight * pathNon
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0, 0, false
+	}
+	return sd / totalWeight, nd / totalWeight, true
+}
+
+// permutations returns all orderings of the given positions.
+func permutations(positions []int) [][]int {
+	if len(positions) <= 1 {
+		return [][]int{append([]int(nil), positions...)}
+	}
+	var result [][]int
+	for i, p := range positions {
+		rest := make([]int, 0, len(positions)-1)
+		rest = append(rest, positions[:i]...)
+		rest = append(rest, positions[i+1:]...)
+		for _, perm := range permutations(rest) {
+			result = append(result, append([]int{p}, perm...))
+		}
+	}
+	return result
+}
+
+// estimateKappa estimates the transition/transversion rate ratio from the
+// aligned codon pairs using the Kimura 2-parameter model. It returns 1 if
+// kappa cannot be estimated.
+func estimateKappa(pairs [][2]string) float64 {
+	ts, tv, sites := 0.0, 0.0, 0.0
+	for _, pair := range pairs {
+		for pos := 0; pos < 3; pos++ {
+			sites++
+			a, b := pair[0][pos], pair[1][pos]
+			if a == b {
+				continue
+			} else if isTransition(a, b) {
+				ts++
+			} else {
+				tv++
+			}
+		}
+	}
+	if sites == 0 {
+		return 1
+	}
+	p, q := ts/sites, tv/sites
+	if q == 0 || 1-2*p-q <= 0 || 1-2*q <= 0 {
+		return 1
+	}
+	transversion := -0.5 * math.Log(1-2*q)
+	transition := -0.5*math.Log(1-2*p-q) + 0.25*math.Log(1-2*q)
+	if transition <= 0 {
+		return 1
+	}
+	return 2 * transition / transversion
+}
+
+// jukesCantor returns the Jukes-Cantor corrected distance for the
+// proportion of differences p, or NaN if p is too large.
+func jukesCantor(p float64) float64 {
+	if p >= 0.75 {
+		return math.NaN()
+	} else if p <= 0 {
+		return 0
+	}
+	return -0.75 * math.Log(1-4*p/3)
+}
+
+// DNDS estimates the numbers of synonymous (dS) and nonsynonymous (dN)
+// substitutions per site between two aligned codon sequences. Codon pairs
+// where either codon is a gap, contains a gap or an ambiguous base, or is a
+// stop codon are skipped. Lowercase and RNA codons are accepted.
+func DNDS(a, b Sequence, opts DNDSOptions) (*DNDSResult, error) {
+	code := StandardGeneticCode
+	if opts.Code != 0 {
+		var err error
+		if code, err = GeneticCodeByID(opts.Code); err != nil {
+			return nil, err
+		}
+	}
+	codonsA, err := codonsOf(a)
+	if err != nil {
+		return nil, err
+	}
+	codonsB, err := codonsOf(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(codonsA) != len(codonsB) {
+		return nil, fmt.Errorf("%s and %s have different numbers of codons: %w", a.ID(), b.ID(), ErrInvalidAlignment)
+	}
+	var pairs [][2]string
+	for i := range codonsA {
+		c1, c2 := normalizeCodon(codonsA[i]), normalizeCodon(codonsB[i])
+		if codonIndex(c1) < 0 || codonIndex(c2) < 0 || code.IsStop(c1) || code.IsStop(c2) {
+			continue
+		}
+		pairs = append(pairs, [2]string{c1, c2})
+	}
+
+	m := &dndsModel{code: code, method: opts.Method, kappa: opts.Kappa}
+	if m.kappa <= 0 {
+		m.kappa = estimateKappa(pairs)
+	}
+	if m.method == YangNielsen {
+		total := float64(2*len(pairs) + 4)
+		for pos := 0; pos < 3; pos++ {
+			m.baseFreq[pos] = make(map[byte]float64)
+			for _, b := range []byte("TCAG") {
+				m.baseFreq[pos][b] = 1 / total
+			}
+			for _, pair := range pairs {
+				m.baseFreq[pos][pair[0][pos]] += 1 / total
+				m.baseFreq[pos][pair[1][pos]] += 1 / total
+			}
+		}
+	}
+
+	result := &DNDSResult{Kappa: m.kappa}
+	if m.method == NeiGojobori {
+		result.Kappa = 1
+	}
+	for _, pair := range pairs {
+		sd, nd, ok := m.differences(pair[0], pair[1])
+		if !ok {
+			continue
+		}
+		s1, n1 := m.sites(pair[0])
+		s2, n2 := m.sites(pair[1])
+		result.S += (s1 + s2) / 2
+		result.N += (n1 + n2) / 2
+		result.Sd += sd
+		result.Nd += nd
+		result.Codons++
+	}
+	if result.Codons == 0 {
+		return nil, fmt.Errorf("%s and %s: %w", a.ID(), b.ID(), ErrNoComparableCodons)
+	}
+	result.PS = result.Sd / result.S
+	result.PN = result.Nd / result.N
+	result.DS = jukesCantor(result.PS)
+	result.DN = jukesCantor(result.PN)
+	result.Omega = result.DN / result.DS
+	return result, nil
+}
+
+// DNDSMatrix holds the pairwise dN/dS estimates of an alignment. Results[i][j]
+// compares the sequences IDs[i] and IDs[j]. It is nil on the diagonal and
+// for pairs without comparable codons.
+type DNDSMatrix struct {
+	IDs     []string
+	Results [][]*DNDSResult
+}
+
+// DNDSMatrix estimates dN/dS between every pair of sequences in the codon
+// alignment. See DNDS. Pairs without comparable codons, such as a sequence
+// consisting only of gaps, are left as nil instead of failing the whole
+// matrix.
+func (a Alignment) DNDSMatrix(opts DNDSOptions) (*DNDSMatrix, error) {
+	m := &DNDSMatrix{
+		IDs:     make([]string, len(a)),
+		Results: make([][]*DNDSResult, len(a)),
+	}
+	for i, s := range a {
+		m.IDs[i] = s.ID()
+		m.Results[i] = make([]*DNDSResult, len(a))
+	}
+	for i := range a {
+		for j := i + 1; j < len(a); j++ {
+			r, err := DNDS(a[i], a[j], opts)
+			if errors.Is(err, ErrNoComparableCodons) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			m.Results[i][j] = r
+			m.Results[j][i] = r
+		}
+	}
+	return m, nil
+}
+
+// WriteTSV writes one row per pair of sequences to w as tab-separated
+// values with a header row. Values that are NaN or infinite, such as the
+// omega of pairs without synonymous substitutions, are written as NA, as
+// are all estimates of pairs without comparable codons.
+func (m *DNDSMatrix) WriteTSV(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("id1\tid2\tcodons\tkappa\tS\tN\tSd\tNd\tpS\tpN\tdS\tdN\tomega\n")
+	for i := range m.IDs {
+		for j := i + 1; j < len(m.IDs); j++ {
+			r := m.Results[i][j]
+			if r == nil {
+				nan := math.NaN()
+				r = &DNDSResult{Kappa: nan, S: nan, N: nan, Sd: nan, Nd: nan, PS: nan, PN: nan, DS: nan, DN: nan, Omega: nan}
+			}
+			fmt.Fprintf(bw, "%s\t%s\t%d", m.IDs[i], m.IDs[j], r.Codons)
+			for _, v := range []float64{r.Kappa, r.S, r.N, r.Sd, r.Nd, r.PS, r.PN, r.DS, r.DN, r.Omega} {
+				bw.WriteByte('\t')
+				bw.WriteString(formatEstimate(v))
+			}
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// formatEstimate formats v with four decimal places, or as NA if v is NaN
+// or infinite.
+func formatEstimate(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "NA"
+	}
+	return fmt.Sprintf("%.4f", v)
+}
@@ -0,0 +1,151 @@
+package gofasta
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDNDS_NeiGojobori(t *testing.T) {
+	a := NewCodonSequence("a", "", "TTATGG")
+	b := NewCodonSequence("b", "", "CTGCGA")
+	r, err := DNDS(a, b, DNDSOptions{})
+	if err != nil {
+		t.Fatalf("DNDS: unexpected error %v", err)
+	}
+	exp := []struct {
+		name             string
+		expected, actual float64
+	}{
+		{"S", 5.0 / 3, r.S},
+		{"N", 13.0 / 3, r.N},
+		{"Sd", 3, r.Sd},
+		{"Nd", 1, r.Nd},
+		{"PN", 3.0 / 13, r.PN},
+	}
+	for _, e := range exp {
+		if math.Abs(e.expected-e.actual) > 1e-9 {
+			t.Errorf("DNDS: expected %s %g, actual %g", e.name, e.expected, e.actual)
+		}
+	}
+	if !math.IsNaN(r.DS) || r.Codons != 2 {
+		t.Errorf("DNDS: expected saturated dS and 2 codons, actual %g and %d", r.DS, r.Codons)
+	}
+}
+
+func TestDNDS_Identical(t *testing.T) {
+	a := NewCharSequence("a", "", "ATG---AAAtaa")
+	b := NewCharSequence("b", "", "ATGCCCAAATAA")
+	r, err := DNDS(a, b, DNDSOptions{})
+	if err != nil {
+		t.Fatalf("DNDS: unexpected error %v", err)
+	}
+	if r.Codons != 2 || r.Sd != 0 || r.Nd != 0 || r.DS != 0 || r.DN != 0 || !math.IsNaN(r.Omega) {
+		t.Errorf("DNDS: unexpected result %#v", r)
+	}
+	if _, err := DNDS(a, NewCharSequence("c", "", "ATG"), DNDSOptions{}); err == nil {
+		t.Errorf("DNDS: expected error for different lengths")
+	}
+	if _, err := DNDS(NewCharSequence("c", "", "---"), NewCharSequence("d", "", "TAA"), DNDSOptions{}); !errors.Is(err, ErrNoComparableCodons) {
+		t.Errorf("DNDS: expected ErrNoComparableCodons, actual %v", err)
+	}
+}
+
+func TestDNDS_YangNielsenConstantPosition(t *testing.T) {
+	// every codon position shows a single base except the third of the
+	// second codon, so the sites must still add up to three per codon
+	a := NewCodonSequence("a", "", "ATGAAA")
+	b := NewCodonSequence("b", "", "ATGAAG")
+	r, err := DNDS(a, b, DNDSOptions{Method: YangNielsen})
+	if err != nil {
+		t.Fatalf("DNDS: unexpected error %v", err)
+	}
+	if math.Abs(r.S+r.N-6) > 1e-9 || r.Sd != 1 || r.Nd != 0 {
+		t.Errorf("DNDS: unexpected result %#v", r)
+	}
+}
+
+func TestDNDS_ModifiedNeiGojobori(t *testing.T) {
+	a := NewCodonSequence("a", "", "TTA")
+	r, err := DNDS(a, a, DNDSOptions{Method: ModifiedNeiGojobori, Kappa: 2})
+	if err != nil {
+		t.Fatalf("DNDS: unexpected error %v", err)
+	}
+	if math.Abs(r.S-1) > 1e-9 || math.Abs(r.N-2) > 1e-9 || r.Kappa != 2 {
+		t.Errorf("DNDS: expected S 1, N 2 and kappa 2, actual %g, %g and %g", r.S, r.N, r.Kappa)
+	}
+}
+
+func TestDNDS_Methods(t *testing.T) {
+	a := NewCodonSequence("a", "", "ATGGCTGCCAAACTGTTTGGAACCCGTGATTACGAA")
+	b := NewCodonSequence("b", "", "ATGGCCGCCAAGCTGTTTGGAACCCGTGATTACGAG")
+	c := NewCodonSequence("c", "", "ATGGCTGCCAGACTGTTTGGAACCCGTGAATACGAA")
+	for _, method := range []DNDSMethod{NeiGojobori, ModifiedNeiGojobori, YangNielsen} {
+		r, err := DNDS(a, c, DNDSOptions{Method: method})
+		if err != nil {
+			t.Fatalf("DNDS: unexpected error %v", err)
+		}
+		if math.Abs(r.S+r.N-3*float64(r.Codons)) > 1e-9 {
+			t.Errorf("DNDS(%d): expected %d sites, actual %g", method, 3*r.Codons, r.S+r.N)
+		}
+		if r.Sd != 0 || r.Nd != 2 || r.DN <= 0 || !math.IsInf(r.Omega, 1) {
+			t.Errorf("DNDS(%d): unexpected result %#v", method, r)
+		}
+		r, _ = DNDS(a, b, DNDSOptions{Method: method})
+		if r.Nd != 0 || r.DS <= 0 || r.Omega != 0 {
+			t.Errorf("DNDS(%d): unexpected result %#v", method, r)
+		}
+	}
+}
+
+func TestAlignment_DNDSMatrix(t *testing.T) {
+	a := Alignment{
+		NewCodonSequence("a", "", "ATGGCTGCCAAACTGTTTGGAACC"),
+		NewCodonSequence("b", "", "ATGGCCGCAAAGCTATTCGGGACT"),
+		NewCodonSequence("c", "", "ATGGCTGCCAGACTGTTTGGAACC"),
+	}
+	m, err := a.DNDSMatrix(DNDSOptions{})
+	if err != nil {
+		t.Fatalf("DNDSMatrix: unexpected error %v", err)
+	}
+	if m.Results[0][0] != nil || m.Results[0][2] != m.Results[2][0] || m.Results[1][2].Codons != 8 {
+		t.Errorf("DNDSMatrix: unexpected results %#v", m.Results)
+	}
+	var buff bytes.Buffer
+	if err := m.WriteTSV(&buff); err != nil {
+		t.Fatalf("WriteTSV: unexpected error %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buff.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "a\tc\t8\t") {
+		t.Errorf("WriteTSV: unexpected output %#v", buff.String())
+	}
+	// a and c differ only by a nonsynonymous substitution, so omega is +Inf
+	if !math.IsInf(m.Results[0][2].Omega, 1) || !strings.HasSuffix(lines[2], "\tNA") {
+		t.Errorf("WriteTSV: expected NA omega, actual %#v", lines[2])
+	}
+}
+
+func TestAlignment_DNDSMatrix_NoComparableCodons(t *testing.T) {
+	a := Alignment{
+		NewCodonSequence("a", "", "ATGGCTGCCAAA"),
+		NewCodonSequence("b", "", "ATGGCCGCAAAG"),
+		NewCodonSequence("c", "", "------------"),
+	}
+	m, err := a.DNDSMatrix(DNDSOptions{})
+	if err != nil {
+		t.Fatalf("DNDSMatrix: unexpected error %v", err)
+	}
+	if m.Results[0][1] == nil || m.Results[0][2] != nil || m.Results[1][2] != nil {
+		t.Errorf("DNDSMatrix: unexpected results %#v", m.Results)
+	}
+	var buff bytes.Buffer
+	if err := m.WriteTSV(&buff); err != nil {
+		t.Fatalf("WriteTSV: unexpected error %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buff.String(), "\n"), "\n")
+	if exp := "a\tc\t0" + strings.Repeat("\tNA", 10); len(lines) != 4 || lines[2] != exp {
+		t.Errorf("WriteTSV: expected %#v, actual %#v", exp, buff.String())
+	}
+}
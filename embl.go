@@ -0,0 +1,150 @@
+package gofasta
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NewEMBLReader creates a new SeqRecordReader that reads EMBL records
+// from r.
+func NewEMBLReader(r io.Reader) *SeqRecordReader {
+	return newSeqRecordReader(r, parseEMBL)
+}
+
+// ReadEMBLFile reads all records in an EMBL file.
+// Compressed files are decompressed transparently.
+func ReadEMBLFile(path string) ([]*SeqRecord, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadEMBL(file)
+}
+
+// ReadEMBL reads all records in an EMBL-formatted io.Reader stream.
+func ReadEMBL(r io.Reader) ([]*SeqRecord, error) {
+	return readSeqRecords(NewEMBLReader(r))
+}
+
+// parseEMBL parses the lines of a single EMBL record. Each line starts with
+// a two-letter line code followed by the content from column 5.
+func parseEMBL(lines []flatLine) (*SeqRecord, error) {
+	record := new(SeqRecord)
+	var seqBuffer strings.Builder
+	var featureLines []flatLine
+	var accessions, definition, keywords, taxonomy []string
+	inSequence := false
+	for _, line := range lines {
+		text := line.text
+		if inSequence {
+			for _, c := range text {
+				if unicode.IsLetter(c) || c == '-' || c == '*' {
+					seqBuffer.WriteRune(c)
+				}
+			}
+			continue
+		}
+		if len(text) < 2 {
+			continue
+		}
+		code := text[:2]
+		content := ""
+		if len(text) > 5 {
+			content = strings.TrimSpace(text[5:])
+		}
+		switch code {
+		case "ID":
+			if err := parseEMBLID(record, content); err != nil {
+				return nil, &ParseError{Line: line.num, Reason: "invalid ID line", Err: err}
+			}
+		case "AC":
+			for _, ac := range strings.Split(content, ";") {
+				if ac = strings.TrimSpace(ac); len(ac) > 0 {
+					accessions = append(accessions, ac)
+				}
+			}
+		case "SV":
+			record.Version = content
+		case "DT":
+			if len(record.Date) == 0 {
+				record.Date = strings.Fields(content + " ")[0]
+			}
+		case "DE":
+			definition = append(definition, content)
+		case "KW":
+			keywords = append(keywords, content)
+		case "OS":
+			if len(record.Organism) == 0 {
+				record.Organism = content
+				record.Source = content
+			}
+		case "OC":
+			taxonomy = append(taxonomy, content)
+		case "FT":
+			// Feature table lines use the same columns as GenBank after
+			// removing the line code
+			featureLines = append(featureLines, flatLine{line.num, "  " + text[2:]})
+		case "SQ":
+			inSequence = true
+		}
+	}
+	if len(record.Name) == 0 {
+		line := 0
+		if len(lines) > 0 {
+			line = lines[0].num
+		}
+		return nil, &ParseError{Line: line, Reason: "missing ID line"}
+	}
+	record.Accessions = accessions
+	if len(accessions) > 0 {
+		record.Accession = accessions[0]
+	}
+	record.Definition = strings.Join(definition, " ")
+	record.Keywords = splitFlatList(strings.Join(keywords, " "))
+	record.Taxonomy = splitFlatList(strings.Join(taxonomy, " "))
+
+	features, err := parseFeatureTable(featureLines)
+	if err != nil {
+		return nil, err
+	}
+	record.Features = features
+	record.Sequence = seqBuffer.String()
+	return record, nil
+}
+
+// parseEMBLID parses an ID line such as
+// "X56734; SV 1; linear; mRNA; STD; PLN; 1859 BP."
+func parseEMBLID(record *SeqRecord, content string) error {
+	fields := strings.Split(strings.TrimSuffix(content, "."), ";")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	record.Name = strings.Fields(fields[0] + " ")[0]
+	if len(fields) < 7 {
+		// Pre-2006 format: "X56734 standard; RNA; PLN; 1859 BP."
+		if len(fields) >= 2 {
+			record.MoleculeType = fields[1]
+		}
+		if len(fields) >= 3 {
+			record.Division = fields[len(fields)-2]
+		}
+	} else {
+		if strings.HasPrefix(fields[1], "SV ") {
+			record.Version = record.Name + "." + strings.TrimSpace(fields[1][3:])
+		}
+		record.Topology = fields[2]
+		record.MoleculeType = fields[3]
+		record.Division = fields[5]
+	}
+	if size := strings.Fields(fields[len(fields)-1]); len(size) == 2 {
+		n, err := strconv.Atoi(size[0])
+		if err != nil {
+			return err
+		}
+		record.Length = n
+	}
+	return nil
+}
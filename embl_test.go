@@ -0,0 +1,71 @@
+package gofasta
+
+import (
+	"strings"
+	"testing"
+)
+
+const emblExample = `ID   AB000001; SV 1; linear; genomic DNA; STD; PRO; 40 BP.
+XX
+AC   AB000001; AB000002;
+XX
+DT   01-JAN-2020 (Rel. 1, Created)
+XX
+DE   Test organism gene for test protein, complete cds, and another
+DE   partial cds.
+XX
+KW   alpha; beta.
+XX
+OS   Escherichia coli
+OC   Bacteria; Proteobacteria; Gammaproteobacteria; Enterobacterales;
+OC   Enterobacteriaceae; Escherichia.
+XX
+FH   Key             Location/Qualifiers
+FH
+FT   source          1..40
+FT                   /organism="Escherichia coli"
+FT                   /db_xref="taxon:562"
+FT   CDS             join(1..6,
+FT                   13..15)
+FT                   /gene="abcA"
+FT                   /product="test protein with a long name that wraps onto
+FT                   the next line"
+FT                   /translation="MA
+FT                   I"
+FT   CDS             complement(<22..30)
+FT                   /locus_tag="T_002"
+FT                   /pseudo
+FT   CDS             <1..8
+FT                   /codon_start=2
+FT                   /protein_id="XYZ1.1"
+FT                   /note="say ""hi"""
+XX
+SQ   Sequence 40 BP; 12 A; 9 C; 9 G; 10 T; 0 other;
+     atggcctaag gcattaacgt tccgcatgac tcaagcttaa                              40
+//
+`
+
+func TestReadEMBL(t *testing.T) {
+	records, err := ReadEMBL(strings.NewReader(emblExample))
+	if err != nil {
+		t.Fatalf("ReadEMBL: unexpected error %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ReadEMBL: expected 1 record, actual %d", len(records))
+	}
+	checkSeqRecord(t, records[0])
+	if r := records[0]; r.MoleculeType != "genomic DNA" || r.Division != "PRO" || r.Date != "01-JAN-2020" {
+		t.Errorf("ReadEMBL: unexpected ID fields %+v", r)
+	}
+}
+
+func TestReadEMBL_Errors(t *testing.T) {
+	for _, data := range []string{
+		"ID   X; SV 1; linear; mRNA; STD; PLN; x BP.\n//\n",
+		"AC   X56734;\n//\n",
+	} {
+		if _, err := ReadEMBL(strings.NewReader(data)); err == nil {
+			t.Errorf("ReadEMBL: expected error for %#v", data)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package gofasta
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCodonLength is returned when a nucleotide sequence cannot be split into
+// codons because its length is not divisible by 3.
+var ErrCodonLength = errors.New("sequence length is not divisible by 3")
+
+// ParseError describes a problem encountered while parsing a sequence file.
+// Line is the 1-based line number where the problem was detected and ID is
+// the identifier of the record being parsed, if known.
+// Use errors.As to retrieve a ParseError from a returned error.
+type ParseError struct {
+	Line   int
+	ID     string
+	Reason string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("line %d", e.Line)
+	if len(e.ID) > 0 {
+		msg += fmt.Sprintf(", record %q", e.ID)
+	}
+	msg += ": " + e.Reason
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,31 @@
+package gofasta
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestParseError_Error(t *testing.T) {
+	err := &ParseError{Line: 4, ID: "a", Reason: "cannot read", Err: io.ErrUnexpectedEOF}
+	exp := `line 4, record "a": cannot read: unexpected EOF`
+	if err.Error() != exp {
+		t.Errorf("Error: expected %#v, actual %#v", exp, err.Error())
+	}
+	err = &ParseError{Line: 1, Reason: "bad header"}
+	exp = "line 1: bad header"
+	if err.Error() != exp {
+		t.Errorf("Error: expected %#v, actual %#v", exp, err.Error())
+	}
+}
+
+func TestParseError_Unwrap(t *testing.T) {
+	var err error = &ParseError{Line: 2, ID: "a", Reason: "invalid codon sequence", Err: ErrCodonLength}
+	if !errors.Is(err, ErrCodonLength) {
+		t.Errorf("Unwrap: expected error to wrap ErrCodonLength")
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 {
+		t.Errorf("Unwrap: expected errors.As to find *ParseError at line 2, actual %#v", perr)
+	}
+}
@@ -0,0 +1,484 @@
+package gofasta
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FaiRecord is a single entry of a FASTA index (.fai) as created by
+// samtools faidx. Offset is the byte offset of the first base of the
+// sequence. LineBases is the number of bases per line and LineWidth is the
+// number of bytes per line including the line terminator. For BGZF
+// compressed files, offsets refer to the uncompressed data.
+type FaiRecord struct {
+	Name      string
+	Length    int64
+	Offset    int64
+	LineBases int64
+	LineWidth int64
+}
+
+// FastaIndex is an index of the sequences in a FASTA file.
+type FastaIndex struct {
+	Records []FaiRecord
+	byName  map[string]int
+}
+
+// NewFastaIndex creates a FastaIndex from a list of records.
+func NewFastaIndex(records []FaiRecord) *FastaIndex {
+	idx := &FastaIndex{Records: records, byName: make(map[string]int)}
+	for i, rec := range records {
+		idx.byName[rec.Name] = i
+	}
+	return idx
+}
+
+// Record returns the index entry of the sequence with the given name.
+func (idx *FastaIndex) Record(name string) (rec FaiRecord, ok bool) {
+	i, ok := idx.byName[name]
+	if !ok {
+		return
+	}
+	return idx.Records[i], true
+}
+
+// Write writes the index in the tab-delimited .fai format.
+func (idx *FastaIndex) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, rec := range idx.Records {
+		fmt.Fprintf(bw, "%s\t%d\t%d\t%d\t%d\n", rec.Name, rec.Length, rec.Offset, rec.LineBases, rec.LineWidth)
+	}
+	return bw.Flush()
+}
+
+// ReadFastaIndex reads an index in the tab-delimited .fai format.
+func ReadFastaIndex(r io.Reader) (*FastaIndex, error) {
+	var records []FaiRecord
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 {
+			return nil, &ParseError{Line: lineNum, Reason: fmt.Sprintf("expected 5 fields, found %d", len(fields))}
+		}
+		var values [4]int64
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, &ParseError{Line: lineNum, ID: fields[0], Reason: "invalid number", Err: err}
+			}
+			values[i] = v
+		}
+		records = append(records, FaiRecord{fields[0], values[0], values[1], values[2], values[3]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewFastaIndex(records), nil
+}
+
+// BuildFastaIndex creates an index by reading an uncompressed FASTA stream.
+// Within a record, all sequence lines except the last must have the same
+// length. Blank lines are only allowed at the end of a record.
+func BuildFastaIndex(r io.Reader) (*FastaIndex, error) {
+	reader := bufio.NewReader(r)
+	var records []FaiRecord
+	var rec *FaiRecord
+	var offset int64
+	var lineNum int
+	var sawShort, sawBlank bool
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lineNum++
+		}
+		width := int64(len(line))
+		bases := int64(len(strings.TrimRight(line, "\r\n")))
+		switch {
+		case strings.HasPrefix(line, ">"):
+			name, _ := parseFastaHeader(strings.TrimRight(line, "\r\n"))
+			records = append(records, FaiRecord{Name: name, Offset: offset + width})
+			rec = &records[len(records)-1]
+			sawShort, sawBlank = false, false
+		case bases == 0:
+			sawBlank = rec != nil
+		case rec == nil:
+			return nil, &ParseError{Line: lineNum, Reason: "sequence data before the first header"}
+		case sawBlank || sawShort:
+			return nil, &ParseError{Line: lineNum, ID: rec.Name, Reason: "inconsistent line length"}
+		case rec.LineBases == 0:
+			rec.LineBases, rec.LineWidth = bases, width
+		// A full-width last line may lack its line terminator at EOF
+		case bases > rec.LineBases || (bases == rec.LineBases && width != rec.LineWidth && !(err == io.EOF && width == bases)):
+			return nil, &ParseError{Line: lineNum, ID: rec.Name, Reason: "inconsistent line length"}
+		case bases < rec.LineBases:
+			sawShort = true
+		}
+		if rec != nil && !strings.HasPrefix(line, ">") {
+			rec.Length += bases
+		}
+		offset += width
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return NewFastaIndex(records), nil
+}
+
+// GziEntry maps the start of a BGZF block in the compressed file to the
+// corresponding position in the uncompressed data.
+type GziEntry struct {
+	CompressedOffset   uint64
+	UncompressedOffset uint64
+}
+
+// GziIndex is the block index of a BGZF file (.gzi) as created by bgzip.
+// The first block, which always starts at offset 0, is not listed.
+type GziIndex []GziEntry
+
+// ReadGziIndex reads a binary .gzi index. The number of entries given in
+// the index must match the size of the data that follows it.
+func ReadGziIndex(r io.Reader) (GziIndex, error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%gziEntrySize != 0 || n != uint64(len(data)/gziEntrySize) {
+		return nil, fmt.Errorf("gzi: %d entries do not match %d bytes of data", n, len(data))
+	}
+	index := make(GziIndex, n)
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// gziEntrySize is the size of a GziEntry in a .gzi index.
+const gziEntrySize = 16
+
+// Write writes the index in the binary .gzi format.
+func (g GziIndex) Write(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(g))); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, []GziEntry(g))
+}
+
+// BuildGziIndex creates a block index by scanning the block headers of a
+// BGZF stream.
+func BuildGziIndex(r io.Reader) (GziIndex, error) {
+	var index GziIndex
+	var coffset, uoffset uint64
+	header := make([]byte, 18)
+	for {
+		if _, err := io.ReadFull(r, header); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if header[0] != 0x1f || header[1] != 0x8b || header[12] != 'B' || header[13] != 'C' {
+			return nil, fmt.Errorf("bgzf: invalid block header at offset %d", coffset)
+		}
+		blockSize := uint64(binary.LittleEndian.Uint16(header[16:])) + 1
+		if blockSize < bgzfMinBlockSize {
+			return nil, fmt.Errorf("bgzf: invalid block size %d at offset %d", blockSize, coffset)
+		}
+		rest := make([]byte, blockSize-18)
+		if _, err := io.ReadFull(r, rest); err != nil {
+			return nil, err
+		}
+		dataSize := uint64(binary.LittleEndian.Uint32(rest[len(rest)-4:]))
+		if coffset > 0 && dataSize > 0 {
+			index = append(index, GziEntry{coffset, uoffset})
+		}
+		coffset += blockSize
+		uoffset += dataSize
+	}
+	return index, nil
+}
+
+// bgzfReaderAt reads uncompressed data at arbitrary offsets from a BGZF
+// file using its block index.
+type bgzfReaderAt struct {
+	r     io.ReaderAt
+	size  int64
+	index GziIndex
+}
+
+func (b *bgzfReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	// Find the last block starting at or before off
+	i := sort.Search(len(b.index), func(i int) bool {
+		return int64(b.index[i].UncompressedOffset) > off
+	})
+	var entry GziEntry
+	if i > 0 {
+		entry = b.index[i-1]
+	}
+	section := io.NewSectionReader(b.r, int64(entry.CompressedOffset), b.size-int64(entry.CompressedOffset))
+	gr, err := gzip.NewReader(section)
+	if err != nil {
+		return 0, err
+	}
+	defer gr.Close()
+	if _, err = io.CopyN(io.Discard, gr, off-int64(entry.UncompressedOffset)); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(gr, p)
+}
+
+// IndexedFasta provides random access to the sequences of an indexed FASTA
+// file, similar to samtools faidx. Both uncompressed and BGZF compressed
+// files are supported.
+type IndexedFasta struct {
+	Index *FastaIndex
+
+	file *os.File
+	r    io.ReaderAt
+}
+
+// OpenIndexedFasta opens a FASTA file for random access using the index
+// stored in path+".fai". For BGZF compressed files, the block index is read
+// from path+".gzi". Missing index files are built in memory by scanning the
+// file; use BuildFastaIndexFile to save them to disk.
+func OpenIndexedFasta(path string) (*IndexedFasta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	c, err := DetectCompression(bufio.NewReader(io.NewSectionReader(f, 0, info.Size())))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	indexed := &IndexedFasta{file: f, r: f}
+	switch c {
+	case NoCompression:
+	case BGZF:
+		index, err := loadGziIndex(path, f, info.Size())
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		indexed.r = &bgzfReaderAt{f, info.Size(), index}
+	default:
+		f.Close()
+		return nil, fmt.Errorf("%s: random access requires BGZF compression, found %s", path, c)
+	}
+	indexed.Index, err = loadFastaIndex(path)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return indexed, nil
+}
+
+func loadGziIndex(path string, f *os.File, size int64) (GziIndex, error) {
+	gziFile, err := os.Open(path + ".gzi")
+	if errors.Is(err, os.ErrNotExist) {
+		return BuildGziIndex(bufio.NewReader(io.NewSectionReader(f, 0, size)))
+	} else if err != nil {
+		return nil, err
+	}
+	defer gziFile.Close()
+	return ReadGziIndex(bufio.NewReader(gziFile))
+}
+
+func loadFastaIndex(path string) (*FastaIndex, error) {
+	faiFile, err := os.Open(path + ".fai")
+	if errors.Is(err, os.ErrNotExist) {
+		r, err := OpenFile(path)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return BuildFastaIndex(r)
+	} else if err != nil {
+		return nil, err
+	}
+	defer faiFile.Close()
+	return ReadFastaIndex(faiFile)
+}
+
+// BuildFastaIndexFile creates the index of a FASTA file and saves it to
+// path+".fai". For BGZF compressed files, the block index is also saved to
+// path+".gzi".
+func BuildFastaIndexFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, c, err := NewDecompressReader(f)
+	if err != nil {
+		return err
+	}
+	idx, err := BuildFastaIndex(r)
+	if err != nil {
+		return err
+	}
+	if err = writeIndexFile(path+".fai", idx.Write); err != nil {
+		return err
+	}
+	if c != BGZF {
+		return nil
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	gzi, err := BuildGziIndex(bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+	return writeIndexFile(path+".gzi", gzi.Write)
+}
+
+func writeIndexFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close closes the underlying file.
+func (f *IndexedFasta) Close() error {
+	return f.file.Close()
+}
+
+// Sequence retrieves a whole sequence by its ID.
+func (f *IndexedFasta) Sequence(name string) (*CharSequence, error) {
+	rec, ok := f.Index.Record(name)
+	if !ok {
+		return nil, fmt.Errorf("sequence %q not found in index", name)
+	}
+	seq, err := f.fetch(rec, 0, rec.Length)
+	if err != nil {
+		return nil, err
+	}
+	return NewCharSequence(name, "", seq), nil
+}
+
+// Fetch retrieves the bases from start (inclusive) to end (exclusive) of
+// the named sequence, using 0-based coordinates. An end beyond the length of
+// the sequence is truncated. The ID of the returned sequence is the region
+// in samtools notation.
+func (f *IndexedFasta) Fetch(name string, start, end int64) (*CharSequence, error) {
+	rec, ok := f.Index.Record(name)
+	if !ok {
+		return nil, fmt.Errorf("sequence %q not found in index", name)
+	}
+	if end > rec.Length {
+		end = rec.Length
+	}
+	if start < 0 || start >= end {
+		return nil, fmt.Errorf("invalid region %s:%d-%d for sequence of length %d", name, start+1, end, rec.Length)
+	}
+	seq, err := f.fetch(rec, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return NewCharSequence(fmt.Sprintf("%s:%d-%d", name, start+1, end), "", seq), nil
+}
+
+// Region retrieves a region given in samtools notation, such as "chr1",
+// "chr1:1000" or "chr1:1,000-2,000". Positions are 1-based and inclusive.
+// If the whole string matches a sequence ID, the entire sequence is
+// returned.
+func (f *IndexedFasta) Region(region string) (*CharSequence, error) {
+	if _, ok := f.Index.Record(region); ok {
+		return f.Sequence(region)
+	}
+	name, start, end, err := ParseRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	if end < 0 {
+		if start == 0 {
+			return f.Sequence(name)
+		}
+		rec, ok := f.Index.Record(name)
+		if !ok {
+			return nil, fmt.Errorf("sequence %q not found in index", name)
+		}
+		end = rec.Length
+	}
+	return f.Fetch(name, start, end)
+}
+
+// ParseRegion parses a region in samtools notation into the sequence name
+// and 0-based, half-open coordinates. If the region does not specify an
+// end, end is -1.
+func ParseRegion(region string) (name string, start, end int64, err error) {
+	i := strings.LastIndex(region, ":")
+	if i < 0 {
+		return region, 0, -1, nil
+	}
+	name = region[:i]
+	coords := strings.Replace(region[i+1:], ",", "", -1)
+	end = -1
+	parts := strings.SplitN(coords, "-", 2)
+	if start, err = strconv.ParseInt(parts[0], 10, 64); err != nil || start < 1 {
+		return "", 0, 0, fmt.Errorf("invalid region %q", region)
+	}
+	start--
+	if len(parts) == 2 {
+		if end, err = strconv.ParseInt(parts[1], 10, 64); err != nil || end <= start {
+			return "", 0, 0, fmt.Errorf("invalid region %q", region)
+		}
+	}
+	return name, start, end, nil
+}
+
+// fetch reads the bases from start to end of a sequence, skipping line
+// terminators.
+func (f *IndexedFasta) fetch(rec FaiRecord, start, end int64) (string, error) {
+	if end <= start {
+		return "", nil
+	}
+	first := rec.position(start)
+	last := rec.position(end - 1)
+	buf := make([]byte, last-first+1)
+	if _, err := f.r.ReadAt(buf, first); err != nil && err != io.EOF {
+		return "", err
+	}
+	buf = bytes.Replace(buf, []byte("\n"), nil, -1)
+	buf = bytes.Replace(buf, []byte("\r"), nil, -1)
+	return string(buf), nil
+}
+
+// position returns the byte offset of the base at the 0-based position i.
+func (rec FaiRecord) position(i int64) int64 {
+	if rec.LineBases == 0 {
+		return rec.Offset + i
+	}
+	return rec.Offset + i/rec.LineBases*rec.LineWidth + i%rec.LineBases
+}
@@ -0,0 +1,187 @@
+package gofasta
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const faidxFasta = ">a desc\nACGTA\nCGTAC\nGT\n>b\nTTTT\nTT\n"
+
+func TestBuildFastaIndex(t *testing.T) {
+	idx, err := BuildFastaIndex(strings.NewReader(faidxFasta))
+	if err != nil {
+		t.Fatalf("BuildFastaIndex: unexpected error %v", err)
+	}
+	var buff bytes.Buffer
+	idx.Write(&buff)
+	exp := "a\t12\t8\t5\t6\nb\t6\t26\t4\t5\n"
+	if exp != buff.String() {
+		t.Errorf("BuildFastaIndex: expected %#v, actual %#v", exp, buff.String())
+	}
+	readIdx, err := ReadFastaIndex(&buff)
+	if err != nil {
+		t.Fatalf("ReadFastaIndex: unexpected error %v", err)
+	}
+	if rec, ok := readIdx.Record("b"); !ok || rec != idx.Records[1] {
+		t.Errorf("ReadFastaIndex: expected %#v, actual %#v", idx.Records[1], rec)
+	}
+}
+
+func TestBuildFastaIndex_NoTrailingNewline(t *testing.T) {
+	idx, err := BuildFastaIndex(strings.NewReader(">a\nACGT\nACGT"))
+	if err != nil {
+		t.Fatalf("BuildFastaIndex: unexpected error %v", err)
+	}
+	exp := FaiRecord{Name: "a", Length: 8, Offset: 3, LineBases: 4, LineWidth: 5}
+	if idx.Records[0] != exp {
+		t.Errorf("BuildFastaIndex: expected %#v, actual %#v", exp, idx.Records[0])
+	}
+}
+
+func TestBuildGziIndex_InvalidBlockSize(t *testing.T) {
+	header := []byte{0x1f, 0x8b, 8, 4, 0, 0, 0, 0, 0, 0xff, 6, 0, 'B', 'C', 2, 0, 10, 0}
+	if _, err := BuildGziIndex(bytes.NewReader(header)); err == nil || !strings.Contains(err.Error(), "invalid block size") {
+		t.Errorf("BuildGziIndex: expected invalid block size error, actual %v", err)
+	}
+}
+
+func TestReadGziIndex_InvalidCount(t *testing.T) {
+	data := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x0f, 1, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := ReadGziIndex(bytes.NewReader(data)); err == nil {
+		t.Errorf("ReadGziIndex: expected error for invalid entry count")
+	}
+}
+
+func TestBuildFastaIndex_Inconsistent(t *testing.T) {
+	for _, data := range []string{
+		">a\nACGT\nAC\nACGT\n",
+		">a\nACGT\nACGTA\n",
+		">a\nACGT\n\nACGT\n",
+	} {
+		if _, err := BuildFastaIndex(strings.NewReader(data)); err == nil {
+			t.Errorf("BuildFastaIndex: expected error for %#v", data)
+		}
+	}
+}
+
+func TestParseRegion(t *testing.T) {
+	cases := []struct {
+		region     string
+		name       string
+		start, end int64
+	}{
+		{"chr1", "chr1", 0, -1},
+		{"chr1:1,000-2,000", "chr1", 999, 2000},
+		{"chr1:5", "chr1", 4, -1},
+		{"HLA-A:1-2", "HLA-A", 0, 2},
+	}
+	for _, c := range cases {
+		name, start, end, err := ParseRegion(c.region)
+		if err != nil || name != c.name || start != c.start || end != c.end {
+			t.Errorf("ParseRegion(%q): expected %s %d %d, actual %s %d %d %v", c.region, c.name, c.start, c.end, name, start, end, err)
+		}
+	}
+	for _, region := range []string{"chr1:0-5", "chr1:5-2", "chr1:x"} {
+		if _, _, _, err := ParseRegion(region); err == nil {
+			t.Errorf("ParseRegion(%q): expected error", region)
+		}
+	}
+}
+
+func TestIndexedFasta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.fa")
+	if err := os.WriteFile(path, []byte(faidxFasta), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := BuildFastaIndexFile(path); err != nil {
+		t.Fatalf("BuildFastaIndexFile: unexpected error %v", err)
+	}
+	f, err := OpenIndexedFasta(path)
+	if err != nil {
+		t.Fatalf("OpenIndexedFasta: unexpected error %v", err)
+	}
+	defer f.Close()
+
+	cases := map[string]string{
+		"a":      "ACGTACGTACGT",
+		"b":      "TTTTTT",
+		"a:4-8":  "TACGT",
+		"a:10":   "CGT",
+		"b:5-20": "TT",
+	}
+	for region, exp := range cases {
+		s, err := f.Region(region)
+		if err != nil {
+			t.Errorf("Region(%q): unexpected error %v", region, err)
+			continue
+		}
+		if exp != s.Sequence() {
+			t.Errorf("Region(%q): expected %#v, actual %#v", region, exp, s.Sequence())
+		}
+	}
+	if s, _ := f.Region("a:4-8"); s.ID() != "a:4-8" {
+		t.Errorf("Region: expected ID %#v, actual %#v", "a:4-8", s.ID())
+	}
+	if _, err := f.Region("c"); err == nil {
+		t.Errorf("Region: expected error for missing sequence")
+	}
+}
+
+func TestIndexedFasta_BGZF(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	seq := make([]byte, 200000)
+	for i := range seq {
+		seq[i] = "ACGT"[rng.Intn(4)]
+	}
+	a := Alignment{NewCharSequence("chr1", "", string(seq)), NewCharSequence("chr2", "", "ACGT")}
+	var buff bytes.Buffer
+	w := NewFastaWriter(&buff)
+	w.LineWidth = 60
+	w.WriteAlignment(a)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.fa.gz")
+	if err := os.WriteFile(path, compressBytes(t, buff.Bytes(), BGZF), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, build := range []bool{false, true} {
+		if build {
+			if err := BuildFastaIndexFile(path); err != nil {
+				t.Fatalf("BuildFastaIndexFile: unexpected error %v", err)
+			}
+		}
+		f, err := OpenIndexedFasta(path)
+		if err != nil {
+			t.Fatalf("OpenIndexedFasta: unexpected error %v", err)
+		}
+		s, err := f.Fetch("chr1", 65000, 140000)
+		if err != nil {
+			t.Fatalf("Fetch: unexpected error %v", err)
+		}
+		if exp := string(seq[65000:140000]); exp != s.Sequence() {
+			t.Errorf("Fetch: sequence mismatch across BGZF blocks")
+		}
+		s, err = f.Sequence("chr2")
+		if err != nil || s.Sequence() != "ACGT" {
+			t.Errorf("Sequence: expected %#v, actual %v %v", "ACGT", s, err)
+		}
+		f.Close()
+	}
+	if _, err := os.Stat(path + ".gzi"); err != nil {
+		t.Errorf("BuildFastaIndexFile: expected .gzi file, %v", err)
+	}
+}
+
+func TestOpenIndexedFasta_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.fa.gz")
+	if err := os.WriteFile(path, compressBytes(t, []byte(faidxFasta), Gzip), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenIndexedFasta(path); err == nil {
+		t.Errorf("OpenIndexedFasta: expected error for plain gzip file")
+	}
+}
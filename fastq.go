@@ -0,0 +1,261 @@
+package gofasta
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Phred quality score encodings used in FASTQ files.
+// Phred33 is the Sanger and Illumina 1.8+ encoding.
+// Phred64 is the encoding used by Illumina 1.3 to 1.7.
+const (
+	Phred33 = 33
+	Phred64 = 64
+)
+
+// QualSequence is a struct for sequencing reads that carry a per-base
+// quality score in addition to the fields of CharSequence. Quality scores
+// are stored as Phred values, independent of the encoding used in the file.
+type QualSequence struct {
+	CharSequence
+	quality []byte
+}
+
+// NewQualSequence constructs a new QualSequence. The quality slice must
+// contain one Phred score per character in sequence.
+func NewQualSequence(name, description, sequence string, quality []byte) *QualSequence {
+	return &QualSequence{CharSequence{name: name, description: description, sequence: sequence}, quality}
+}
+
+// Quality returns the Phred quality scores of the sequence.
+func (s *QualSequence) Quality() []byte {
+	return s.quality
+}
+
+// SetQuality sets the Phred quality scores of the sequence.
+func (s *QualSequence) SetQuality(quality []byte) {
+	s.quality = quality
+}
+
+// EncodeQuality encodes Phred quality scores into a FASTQ quality string
+// using the given offset, usually Phred33 or Phred64.
+func EncodeQuality(quality []byte, offset int) (string, error) {
+	buff := make([]byte, len(quality))
+	for i, q := range quality {
+		c := int(q) + offset
+		if c > '~' {
+			return "", fmt.Errorf("quality score %d at position %d cannot be encoded with offset %d", q, i, offset)
+		}
+		buff[i] = byte(c)
+	}
+	return string(buff), nil
+}
+
+// DecodeQuality decodes a FASTQ quality string into Phred quality scores
+// using the given offset, usually Phred33 or Phred64.
+func DecodeQuality(encoded string, offset int) ([]byte, error) {
+	quality := make([]byte, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		c := int(encoded[i])
+		if c < offset || c > '~' {
+			return nil, fmt.Errorf("invalid quality character %q at position %d for offset %d", encoded[i], i, offset)
+		}
+		quality[i] = byte(c - offset)
+	}
+	return quality, nil
+}
+
+// ReadFastqFile reads a FASTQ file into an Alignment of QualSequence.
+// Compressed files are decompressed transparently.
+func ReadFastqFile(path string, offset int) (Alignment, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFastq(file, offset)
+}
+
+// ReadFastq reads a FASTQ-formatted io.Reader stream into an Alignment of
+// QualSequence. Because QualSequence is a Sequence, the result can be
+// written as FASTA using Alignment.WriteFasta or FastaWriter.
+func ReadFastq(r io.Reader, offset int) (sequences Alignment, err error) {
+	reader := NewFastqReader(r, offset)
+	for reader.Next() {
+		sequences = append(sequences, reader.Record())
+	}
+	if err = reader.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// FastqReader reads FASTQ-formatted records one at a time from an
+// io.Reader. Sequence and quality strings may be wrapped over multiple
+// lines. Because quality lines may start with '@' or '+', the end of the
+// quality string is determined by the length of the sequence.
+type FastqReader struct {
+	reader *bufio.Reader
+	offset int
+	line   int
+
+	record *QualSequence
+	err    error
+	done   bool
+}
+
+// NewFastqReader creates a new FastqReader that reads from r and decodes
+// quality scores using the given offset, usually Phred33 or Phred64.
+func NewFastqReader(r io.Reader, offset int) *FastqReader {
+	return &FastqReader{
+		reader: bufio.NewReader(r),
+		offset: offset,
+	}
+}
+
+// Next advances the reader to the next record, which will then be available
+// through Record. It returns false when there are no more records or an
+// error occurred. After Next returns false, Err returns the error that
+// occurred, if any.
+func (r *FastqReader) Next() bool {
+	r.record = nil
+	if r.done {
+		return false
+	}
+	// Skip blank lines before the header
+	var line string
+	var err error
+	for len(line) == 0 {
+		line, err = r.readLine()
+		if err == io.EOF && len(line) == 0 {
+			r.done = true
+			return false
+		} else if err != nil && err != io.EOF {
+			return r.fail(&ParseError{Line: r.line, Reason: "cannot read input", Err: err})
+		}
+	}
+	if !strings.HasPrefix(line, "@") {
+		return r.fail(&ParseError{Line: r.line, Reason: "expected header line starting with '@'"})
+	}
+	name, desc := parseFastaHeader(line)
+	headerLine := r.line
+
+	var seqBuffer bytes.Buffer
+	for {
+		line, err = r.readLine()
+		if strings.HasPrefix(line, "+") {
+			break
+		}
+		if err != nil {
+			return r.fail(&ParseError{Line: r.line, ID: name, Reason: "missing '+' separator line", Err: err})
+		}
+		seqBuffer.WriteString(line)
+	}
+	var qualBuffer bytes.Buffer
+	for qualBuffer.Len() < seqBuffer.Len() {
+		line, err = r.readLine()
+		qualBuffer.WriteString(line)
+		if err != nil {
+			break
+		}
+	}
+	if qualBuffer.Len() != seqBuffer.Len() {
+		return r.fail(&ParseError{Line: r.line, ID: name, Reason: fmt.Sprintf("quality length %d does not match sequence length %d", qualBuffer.Len(), seqBuffer.Len())})
+	}
+	quality, qerr := DecodeQuality(qualBuffer.String(), r.offset)
+	if qerr != nil {
+		return r.fail(&ParseError{Line: headerLine, ID: name, Reason: "invalid quality string", Err: qerr})
+	}
+	if err == io.EOF {
+		r.done = true
+	} else if err != nil {
+		return r.fail(&ParseError{Line: r.line, ID: name, Reason: "cannot read input", Err: err})
+	}
+	r.record = NewQualSequence(name, desc, seqBuffer.String(), quality)
+	return true
+}
+
+// Record returns the most recent record read by a call to Next.
+func (r *FastqReader) Record() *QualSequence {
+	return r.record
+}
+
+// Err returns the first non-EOF error that was encountered by the reader.
+func (r *FastqReader) Err() error {
+	return r.err
+}
+
+// readLine reads a single line without its line terminator.
+func (r *FastqReader) readLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if len(line) > 0 {
+		r.line++
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+func (r *FastqReader) fail(err error) bool {
+	r.done = true
+	r.record = nil
+	r.err = err
+	return false
+}
+
+// FastqWriter writes sequences with quality scores in the FASTQ format.
+// Sequence and quality strings are each written on a single line.
+// Output is buffered; Flush must be called after the last record.
+type FastqWriter struct {
+	w      *bufio.Writer
+	offset int
+}
+
+// NewFastqWriter creates a new FastqWriter that writes to w and encodes
+// quality scores using the given offset, usually Phred33 or Phred64.
+func NewFastqWriter(w io.Writer, offset int) *FastqWriter {
+	return &FastqWriter{w: bufio.NewWriter(w), offset: offset}
+}
+
+// Write writes a single sequence record.
+func (w *FastqWriter) Write(s *QualSequence) error {
+	if len(s.quality) != len(s.sequence) {
+		return fmt.Errorf("sequence %q: quality length %d does not match sequence length %d", s.ID(), len(s.quality), len(s.sequence))
+	}
+	encoded, err := EncodeQuality(s.quality, w.offset)
+	if err != nil {
+		return fmt.Errorf("sequence %q: %w", s.ID(), err)
+	}
+	w.w.WriteByte('@')
+	w.w.WriteString(s.ID())
+	if len(s.Description()) > 0 {
+		w.w.WriteByte(' ')
+		w.w.WriteString(s.Description())
+	}
+	w.w.WriteByte('\n')
+	w.w.WriteString(s.Sequence())
+	w.w.WriteString("\n+\n")
+	w.w.WriteString(encoded)
+	return w.w.WriteByte('\n')
+}
+
+// WriteAlignment writes all sequences in the alignment and flushes the
+// output. Every sequence must be a *QualSequence.
+func (w *FastqWriter) WriteAlignment(a Alignment) error {
+	for _, s := range a {
+		qs, ok := s.(*QualSequence)
+		if !ok {
+			return fmt.Errorf("sequence %q has no quality scores", s.ID())
+		}
+		if err := w.Write(qs); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *FastqWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,110 @@
+package gofasta
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeQuality(t *testing.T) {
+	q, err := DecodeQuality("!+5I", Phred33)
+	if err != nil {
+		t.Fatalf("DecodeQuality: unexpected error %v", err)
+	}
+	if exp := []byte{0, 10, 20, 40}; !bytes.Equal(exp, q) {
+		t.Errorf("DecodeQuality: expected %v, actual %v", exp, q)
+	}
+	q, _ = DecodeQuality("@J", Phred64)
+	if exp := []byte{0, 10}; !bytes.Equal(exp, q) {
+		t.Errorf("DecodeQuality: expected %v, actual %v", exp, q)
+	}
+	if _, err := DecodeQuality("5", Phred64); err == nil {
+		t.Errorf("DecodeQuality: expected error for character below offset")
+	}
+}
+
+func TestEncodeQuality(t *testing.T) {
+	s, err := EncodeQuality([]byte{0, 10, 20, 40}, Phred33)
+	if err != nil || s != "!+5I" {
+		t.Errorf("EncodeQuality: expected %#v, actual %#v %v", "!+5I", s, err)
+	}
+	if _, err := EncodeQuality([]byte{70}, Phred64); err == nil {
+		t.Errorf("EncodeQuality: expected error for out of range score")
+	}
+}
+
+func TestFastqReader(t *testing.T) {
+	r := strings.NewReader("@r1 first read\n" +
+		"ACGT\n" +
+		"AC\n" +
+		"+r1\n" +
+		"@@@+\n" +
+		"II\n" +
+		"\n" +
+		"@r2\n" +
+		"GG\n" +
+		"+\n" +
+		"!!")
+	a, err := ReadFastq(r, Phred33)
+	if err != nil {
+		t.Fatalf("ReadFastq: unexpected error %v", err)
+	}
+	if len(a) != 2 {
+		t.Fatalf("ReadFastq: expected 2 records, actual %d", len(a))
+	}
+	s := a[0].(*QualSequence)
+	if s.ID() != "r1" || s.Description() != "first read" || s.Sequence() != "ACGTAC" {
+		t.Errorf("ReadFastq: unexpected record %#v", s)
+	}
+	if exp := []byte{31, 31, 31, 10, 40, 40}; !bytes.Equal(exp, s.Quality()) {
+		t.Errorf("ReadFastq: expected quality %v, actual %v", exp, s.Quality())
+	}
+	if s := a[1].(*QualSequence); s.ID() != "r2" || !bytes.Equal([]byte{0, 0}, s.Quality()) {
+		t.Errorf("ReadFastq: unexpected record %#v", s)
+	}
+}
+
+func TestFastqReader_Errors(t *testing.T) {
+	for _, data := range []string{
+		">r1\nACGT\n+\nIIII\n",
+		"@r1\nACGT\nIIII\n",
+		"@r1\nACGT\n+\nIII",
+		"@r1\nACGT\n+\nII I\n",
+	} {
+		_, err := ReadFastq(strings.NewReader(data), Phred33)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ReadFastq: expected *ParseError for %#v, actual %v", data, err)
+		}
+	}
+}
+
+func TestFastqWriter(t *testing.T) {
+	a := Alignment{
+		NewQualSequence("r1", "first read", "ACGT", []byte{0, 10, 20, 40}),
+		NewQualSequence("r2", "", "GG", []byte{30, 30}),
+	}
+	var buff bytes.Buffer
+	if err := NewFastqWriter(&buff, Phred33).WriteAlignment(a); err != nil {
+		t.Fatalf("WriteAlignment: unexpected error %v", err)
+	}
+	exp := "@r1 first read\nACGT\n+\n!+5I\n@r2\nGG\n+\n??\n"
+	if exp != buff.String() {
+		t.Errorf("WriteAlignment: expected %#v, actual %#v", exp, buff.String())
+	}
+	err := NewFastqWriter(&buff, Phred33).WriteAlignment(Alignment{NewCharSequence("a", "", "ACGT")})
+	if err == nil {
+		t.Errorf("WriteAlignment: expected error for sequence without quality scores")
+	}
+}
+
+func TestFastqToFasta(t *testing.T) {
+	a, err := ReadFastq(strings.NewReader("@r1 first read\nACGT\n+\n!+5I\n"), Phred33)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := ">r1 first read\nACGT\n"; exp != a.ToFasta() {
+		t.Errorf("ToFasta: expected %#v, actual %#v", exp, a.ToFasta())
+	}
+}
@@ -0,0 +1,450 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SeqRecord is an annotated sequence record read from a GenBank or EMBL
+// flat file.
+type SeqRecord struct {
+	Name         string
+	Length       int
+	MoleculeType string
+	Topology     string
+	Division     string
+	Date         string
+	Definition   string
+	Accession    string
+	Accessions   []string
+	Version      string
+	Keywords     []string
+	Source       string
+	Organism     string
+	Taxonomy     []string
+	Features     []Feature
+	Sequence     string
+}
+
+// Qualifier is a name and value pair describing a feature, such as
+// /product="beta-glucosidase". Value is empty for qualifiers without a
+// value, such as /pseudo. Quotes around the value are removed.
+type Qualifier struct {
+	Name  string
+	Value string
+}
+
+// Feature is an entry of the feature table of a GenBank or EMBL record.
+// Location holds the unparsed location string.
+type Feature struct {
+	Key        string
+	Location   string
+	Qualifiers []Qualifier
+}
+
+// Qualifier returns the value of the first qualifier with the given name.
+func (f Feature) Qualifier(name string) (string, bool) {
+	for _, q := range f.Qualifiers {
+		if q.Name == name {
+			return q.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseLocation parses the location string of the feature.
+func (f Feature) ParseLocation() (*Location, error) {
+	return ParseLocation(f.Location)
+}
+
+// FeaturesByKey returns all features with the given key, such as "CDS".
+func (r *SeqRecord) FeaturesByKey(key string) (features []Feature) {
+	for _, f := range r.Features {
+		if f.Key == key {
+			features = append(features, f)
+		}
+	}
+	return
+}
+
+// ID returns the accession with version of the record if available,
+// otherwise the name of the record.
+func (r *SeqRecord) ID() string {
+	if len(r.Version) > 0 {
+		return r.Version
+	}
+	if len(r.Accession) > 0 {
+		return r.Accession
+	}
+	return r.Name
+}
+
+// CharSequence returns the whole record as a CharSequence using the ID and
+// definition of the record.
+func (r *SeqRecord) CharSequence() *CharSequence {
+	return NewCharSequence(r.ID(), r.Definition, r.Sequence)
+}
+
+// featureID returns a name for the feature from its qualifiers, or the
+// record ID and location if no naming qualifier is present.
+func (r *SeqRecord) featureID(f Feature) string {
+	for _, name := range []string{"protein_id", "locus_tag", "gene"} {
+		if v, ok := f.Qualifier(name); ok && len(v) > 0 {
+			return v
+		}
+	}
+	return r.ID() + ":" + strings.Join(strings.Fields(f.Location), "")
+}
+
+// FeatureSequence extracts the spliced sequence of a feature as a
+// CharSequence. The ID is taken from the protein_id, locus_tag or gene
+// qualifier, and the description from the product qualifier.
+func (r *SeqRecord) FeatureSequence(f Feature) (*CharSequence, error) {
+	loc, err := f.ParseLocation()
+	if err != nil {
+		return nil, err
+	}
+	seq, err := loc.Extract(r.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	product, _ := f.Qualifier("product")
+	return NewCharSequence(r.featureID(f), product, strings.ToUpper(seq)), nil
+}
+
+// FeatureCodonSequence extracts the spliced sequence of a coding feature as
+// a CodonSequence. The reading frame is set using the codon_start
+// qualifier. For partial features, an incomplete codon at the end is
+// removed. Otherwise, the spliced length must be divisible by 3.
+func (r *SeqRecord) FeatureCodonSequence(f Feature) (*CodonSequence, error) {
+	s, err := r.FeatureSequence(f)
+	if err != nil {
+		return nil, err
+	}
+	seq := s.Sequence()
+	if v, ok := f.Qualifier("codon_start"); ok {
+		start, err := strconv.Atoi(v)
+		if err != nil || start < 1 || start > 3 || start > len(seq)+1 {
+			return nil, fmt.Errorf("feature %s: invalid codon_start %q", s.ID(), v)
+		}
+		seq = seq[start-1:]
+	}
+	if loc, _ := f.ParseLocation(); loc.IsPartial() {
+		seq = seq[:len(seq)-len(seq)%3]
+	}
+	codonSeq, err := NewCodonSequenceChecked(s.ID(), s.Description(), seq)
+	if err != nil {
+		return nil, fmt.Errorf("feature %s: %w", s.ID(), err)
+	}
+	return codonSeq, nil
+}
+
+// SeqRecordReader reads GenBank or EMBL records one at a time from an
+// io.Reader.
+type SeqRecordReader struct {
+	scanner *bufio.Scanner
+	parse   func([]flatLine) (*SeqRecord, error)
+	line    int
+
+	record *SeqRecord
+	err    error
+	done   bool
+}
+
+// flatLine is a line of a flat file record with its line number.
+type flatLine struct {
+	num  int
+	text string
+}
+
+// NewGenBankReader creates a new SeqRecordReader that reads GenBank
+// records from r.
+func NewGenBankReader(r io.Reader) *SeqRecordReader {
+	return newSeqRecordReader(r, parseGenBank)
+}
+
+func newSeqRecordReader(r io.Reader, parse func([]flatLine) (*SeqRecord, error)) *SeqRecordReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	return &SeqRecordReader{scanner: scanner, parse: parse}
+}
+
+// Next advances the reader to the next record, which will then be available
+// through Record. It returns false when there are no more records or an
+// error occurred. After Next returns false, Err returns the error that
+// occurred, if any.
+func (r *SeqRecordReader) Next() bool {
+	r.record = nil
+	if r.done {
+		return false
+	}
+	var lines []flatLine
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimRight(r.scanner.Text(), "\r")
+		if strings.HasPrefix(text, "//") {
+			record, err := r.parse(lines)
+			if err != nil {
+				r.done = true
+				r.err = err
+				return false
+			}
+			r.record = record
+			return true
+		}
+		if len(lines) == 0 && len(strings.TrimSpace(text)) == 0 {
+			continue
+		}
+		lines = append(lines, flatLine{r.line, text})
+	}
+	r.done = true
+	if err := r.scanner.Err(); err != nil {
+		r.err = &ParseError{Line: r.line, Reason: "cannot read input", Err: err}
+	} else if len(lines) > 0 {
+		r.err = &ParseError{Line: r.line, Reason: "missing '//' at end of record", Err: io.ErrUnexpectedEOF}
+	}
+	return false
+}
+
+// Record returns the most recent record read by a call to Next.
+func (r *SeqRecordReader) Record() *SeqRecord {
+	return r.record
+}
+
+// Err returns the first non-EOF error that was encountered by the reader.
+func (r *SeqRecordReader) Err() error {
+	return r.err
+}
+
+// ReadGenBankFile reads all records in a GenBank file.
+// Compressed files are decompressed transparently.
+func ReadGenBankFile(path string) ([]*SeqRecord, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadGenBank(file)
+}
+
+// ReadGenBank reads all records in a GenBank-formatted io.Reader stream.
+func ReadGenBank(r io.Reader) ([]*SeqRecord, error) {
+	return readSeqRecords(NewGenBankReader(r))
+}
+
+func readSeqRecords(reader *SeqRecordReader) (records []*SeqRecord, err error) {
+	for reader.Next() {
+		records = append(records, reader.Record())
+	}
+	if err = reader.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// parseGenBank parses the lines of a single GenBank record.
+func parseGenBank(lines []flatLine) (*SeqRecord, error) {
+	record := new(SeqRecord)
+	var seqBuffer strings.Builder
+	section := ""
+	var featureLines []flatLine
+	for i := 0; i < len(lines); i++ {
+		text := lines[i].text
+		keyword := ""
+		if len(text) > 0 && text[0] != ' ' {
+			keyword = strings.Fields(text)[0]
+			section = keyword
+		} else if strings.HasPrefix(text, "  ") && len(strings.TrimSpace(text)) > 0 && text[2] != ' ' {
+			// Sub-keyword such as ORGANISM
+			keyword = strings.Fields(text)[0]
+		}
+		content := strings.TrimSpace(text)
+		if len(keyword) > 0 {
+			content = strings.TrimSpace(content[len(keyword):])
+		}
+
+		switch {
+		case section == "FEATURES":
+			if len(keyword) == 0 {
+				featureLines = append(featureLines, lines[i])
+			}
+			continue
+		case section == "ORIGIN":
+			for _, c := range content {
+				if unicode.IsLetter(c) || c == '-' || c == '*' {
+					seqBuffer.WriteRune(c)
+				}
+			}
+			continue
+		}
+		// Gather continuation lines
+		var continued []string
+		for i+1 < len(lines) && strings.HasPrefix(lines[i+1].text, "            ") {
+			i++
+			continued = append(continued, strings.TrimSpace(lines[i].text))
+		}
+		if len(continued) > 0 && keyword != "ORGANISM" {
+			content += " " + strings.Join(continued, " ")
+		}
+		switch keyword {
+		case "LOCUS":
+			parseLocus(record, content)
+		case "DEFINITION":
+			record.Definition = content
+		case "ACCESSION":
+			record.Accessions = strings.Fields(content)
+			if len(record.Accessions) > 0 {
+				record.Accession = record.Accessions[0]
+			}
+		case "VERSION":
+			if fields := strings.Fields(content); len(fields) > 0 {
+				record.Version = fields[0]
+			}
+		case "KEYWORDS":
+			record.Keywords = splitFlatList(content)
+		case "SOURCE":
+			record.Source = content
+		case "ORGANISM":
+			// The organism name is followed by the taxonomy on the
+			// continuation lines
+			record.Organism = content
+			record.Taxonomy = splitFlatList(strings.Join(continued, " "))
+		}
+	}
+	features, err := parseFeatureTable(featureLines)
+	if err != nil {
+		return nil, err
+	}
+	record.Features = features
+	record.Sequence = seqBuffer.String()
+	if len(record.Name) == 0 {
+		line := 0
+		if len(lines) > 0 {
+			line = lines[0].num
+		}
+		return nil, &ParseError{Line: line, Reason: "missing LOCUS line"}
+	}
+	return record, nil
+}
+
+// parseLocus parses the fields of the LOCUS line.
+func parseLocus(record *SeqRecord, content string) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return
+	}
+	record.Name = fields[0]
+	for i := 1; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case i+1 < len(fields) && (fields[i+1] == "bp" || fields[i+1] == "aa"):
+			record.Length, _ = strconv.Atoi(f)
+			i++
+		case f == "linear" || f == "circular":
+			record.Topology = f
+		case len(f) == 11 && f[2] == '-' && f[6] == '-':
+			record.Date = f
+		case len(f) == 3 && strings.ToUpper(f) == f && len(record.MoleculeType) > 0:
+			record.Division = f
+		case len(record.MoleculeType) == 0:
+			record.MoleculeType = f
+		}
+	}
+}
+
+// splitFlatList splits a list such as "Eukaryota; Fungi; Ascomycota." into
+// its items.
+func splitFlatList(s string) (items []string) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return
+}
+
+// parseFeatureTable parses feature table lines in which the feature key
+// starts at column 5 and the location and qualifiers start at column 21.
+func parseFeatureTable(lines []flatLine) (features []Feature, err error) {
+	var current *Feature
+	var qualifier *Qualifier
+	inQuote := false
+	for _, line := range lines {
+		text := line.text
+		if len(strings.TrimSpace(text)) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(text, "     ") {
+			return nil, &ParseError{Line: line.num, Reason: "invalid feature table line"}
+		}
+		key := ""
+		if len(text) > 5 {
+			key = strings.TrimSpace(text[5:min(21, len(text))])
+		}
+		value := ""
+		if len(text) > 21 {
+			value = strings.TrimRight(text[21:], " ")
+		}
+		switch {
+		case len(key) > 0 && !inQuote:
+			features = append(features, Feature{Key: key, Location: strings.TrimSpace(value)})
+			current = &features[len(features)-1]
+			qualifier = nil
+		case current == nil:
+			return nil, &ParseError{Line: line.num, Reason: "qualifier before first feature"}
+		case inQuote:
+			sep := " "
+			if qualifier.Name == "translation" {
+				sep = ""
+			}
+			qualifier.Value += sep + strings.TrimSpace(value)
+		case strings.HasPrefix(value, "/"):
+			name, val := value[1:], ""
+			if j := strings.Index(name, "="); j >= 0 {
+				name, val = name[:j], name[j+1:]
+			}
+			current.Qualifiers = append(current.Qualifiers, Qualifier{Name: name, Value: val})
+			qualifier = &current.Qualifiers[len(current.Qualifiers)-1]
+		case qualifier == nil:
+			current.Location += strings.TrimSpace(value)
+		default:
+			qualifier.Value += " " + strings.TrimSpace(value)
+		}
+		if qualifier != nil {
+			inQuote = strings.HasPrefix(qualifier.Value, "\"") && !closedQuote(qualifier.Value)
+		}
+	}
+	if inQuote {
+		return nil, &ParseError{Line: lines[len(lines)-1].num, Reason: "unterminated qualifier value"}
+	}
+	for i := range features {
+		for j, q := range features[i].Qualifiers {
+			features[i].Qualifiers[j].Value = unquoteQualifier(q.Value)
+		}
+	}
+	return features, nil
+}
+
+// closedQuote tells whether a quoted qualifier value has its closing quote.
+// Quotes inside values are escaped by doubling them.
+func closedQuote(v string) bool {
+	n := 0
+	for i := 1; i < len(v); i++ {
+		if v[i] == '"' {
+			n++
+		}
+	}
+	return n%2 == 1 && strings.HasSuffix(v, "\"")
+}
+
+func unquoteQualifier(v string) string {
+	if len(v) >= 2 && strings.HasPrefix(v, "\"") && strings.HasSuffix(v, "\"") {
+		v = v[1 : len(v)-1]
+	}
+	return strings.Replace(v, "\"\"", "\"", -1)
+}
@@ -0,0 +1,133 @@
+package gofasta
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const genBankExample = `LOCUS       TEST01                    40 bp    DNA     linear   BCT 01-JAN-2020
+DEFINITION  Test organism gene for test protein, complete cds, and another
+            partial cds.
+ACCESSION   AB000001 AB000002
+VERSION     AB000001.1
+KEYWORDS    alpha; beta.
+SOURCE      Escherichia coli
+  ORGANISM  Escherichia coli
+            Bacteria; Proteobacteria; Gammaproteobacteria; Enterobacterales;
+            Enterobacteriaceae; Escherichia.
+REFERENCE   1  (bases 1 to 40)
+  AUTHORS   Doe,J.
+  TITLE     Direct Submission
+FEATURES             Location/Qualifiers
+     source          1..40
+                     /organism="Escherichia coli"
+                     /db_xref="taxon:562"
+     CDS             join(1..6,
+                     13..15)
+                     /gene="abcA"
+                     /product="test protein with a long name that wraps onto
+                     the next line"
+                     /translation="MA
+                     I"
+     CDS             complement(<22..30)
+                     /locus_tag="T_002"
+                     /pseudo
+     CDS             <1..8
+                     /codon_start=2
+                     /protein_id="XYZ1.1"
+                     /note="say ""hi"""
+ORIGIN      
+        1 atggcctaag gcattaacgt tccgcatgac tcaagcttaa
+//
+`
+
+func TestReadGenBank(t *testing.T) {
+	records, err := ReadGenBank(strings.NewReader(genBankExample + "\n" + genBankExample))
+	if err != nil {
+		t.Fatalf("ReadGenBank: unexpected error %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("ReadGenBank: expected 2 records, actual %d", len(records))
+	}
+	checkSeqRecord(t, records[0])
+	if records[0].Name != "TEST01" || records[0].Division != "BCT" || records[0].Date != "01-JAN-2020" {
+		t.Errorf("ReadGenBank: unexpected LOCUS fields %+v", records[0])
+	}
+}
+
+// checkSeqRecord checks the contents of the example record, which is shared
+// by the GenBank and EMBL tests.
+func checkSeqRecord(t *testing.T, r *SeqRecord) {
+	if r.Length != 40 || r.Topology != "linear" {
+		t.Errorf("unexpected length or topology %d %s", r.Length, r.Topology)
+	}
+	if r.ID() != "AB000001.1" || r.Accession != "AB000001" || len(r.Accessions) != 2 {
+		t.Errorf("unexpected accession %s %s %v", r.ID(), r.Accession, r.Accessions)
+	}
+	if exp := "Test organism gene for test protein, complete cds, and another partial cds."; r.Definition != exp {
+		t.Errorf("expected definition %#v, actual %#v", exp, r.Definition)
+	}
+	if exp := []string{"alpha", "beta"}; !reflect.DeepEqual(exp, r.Keywords) {
+		t.Errorf("expected keywords %v, actual %v", exp, r.Keywords)
+	}
+	if r.Organism != "Escherichia coli" {
+		t.Errorf("expected organism %#v, actual %#v", "Escherichia coli", r.Organism)
+	}
+	expTaxonomy := []string{"Bacteria", "Proteobacteria", "Gammaproteobacteria", "Enterobacterales", "Enterobacteriaceae", "Escherichia"}
+	if !reflect.DeepEqual(expTaxonomy, r.Taxonomy) {
+		t.Errorf("expected taxonomy %v, actual %v", expTaxonomy, r.Taxonomy)
+	}
+	if exp := "atggcctaaggcattaacgttccgcatgactcaagcttaa"; r.Sequence != exp {
+		t.Errorf("expected sequence %#v, actual %#v", exp, r.Sequence)
+	}
+	if len(r.Features) != 4 || len(r.FeaturesByKey("CDS")) != 3 {
+		t.Fatalf("expected 4 features, actual %+v", r.Features)
+	}
+	cds := r.Features[1]
+	if cds.Location != "join(1..6,13..15)" {
+		t.Errorf("expected location %#v, actual %#v", "join(1..6,13..15)", cds.Location)
+	}
+	if v, _ := cds.Qualifier("product"); v != "test protein with a long name that wraps onto the next line" {
+		t.Errorf("unexpected product %#v", v)
+	}
+	if v, _ := cds.Qualifier("translation"); v != "MAI" {
+		t.Errorf("expected translation %#v, actual %#v", "MAI", v)
+	}
+	if v, ok := r.Features[2].Qualifier("pseudo"); !ok || v != "" {
+		t.Errorf("expected empty pseudo qualifier, actual %#v %v", v, ok)
+	}
+	if v, _ := r.Features[3].Qualifier("note"); v != `say "hi"` {
+		t.Errorf("expected note %#v, actual %#v", `say "hi"`, v)
+	}
+
+	cases := []struct {
+		id, seq, prot string
+	}{
+		{"abcA", "ATGGCCATT", "MAI"},
+		{"T_002", "GTCATGCGG", "VMR"},
+		{"XYZ1.1", "TGGCCT", "WP"},
+	}
+	for i, f := range r.FeaturesByKey("CDS") {
+		s, err := r.FeatureCodonSequence(f)
+		if err != nil {
+			t.Errorf("FeatureCodonSequence: unexpected error %v", err)
+			continue
+		}
+		if s.ID() != cases[i].id || s.Sequence() != cases[i].seq || s.Prot() != cases[i].prot {
+			t.Errorf("FeatureCodonSequence: expected %v, actual %s %s %s", cases[i], s.ID(), s.Sequence(), s.Prot())
+		}
+	}
+}
+
+func TestReadGenBank_Errors(t *testing.T) {
+	for _, data := range []string{
+		"LOCUS       TEST01 40 bp DNA\n",
+		"DEFINITION  no locus\n//\n",
+		"LOCUS       TEST01 40 bp DNA\nFEATURES             Location/Qualifiers\n     CDS             1..3\n                     /note=\"open\n//\n",
+	} {
+		if _, err := ReadGenBank(strings.NewReader(data)); err == nil {
+			t.Errorf("ReadGenBank: expected error for %#v", data)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package gofasta
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGeneticCodes(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5, 6, 9, 10, 11, 12, 13, 14, 16, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33}
+	if len(ids) != len(GeneticCodes) {
+		t.Errorf("GeneticCodes: expected %d tables, actual %d", len(ids), len(GeneticCodes))
+	}
+	for _, id := range ids {
+		if _, err := GeneticCodeByID(id); err != nil {
+			t.Errorf("GeneticCodeByID: unexpected error %v", err)
+		}
+	}
+	if exp := "W"; GeneticCodes[32].TranslateCodon("TAG") != exp || !GeneticCodes[32].IsStart("GTG") {
+		t.Errorf("GeneticCodes: unexpected table 32 %#v", GeneticCodes[32])
+	}
+	if _, err := GeneticCodeByID(7); err == nil {
+		t.Errorf("GeneticCodeByID: expected error for table 7")
+	}
+	if !reflect.DeepEqual(GeneticCode, StandardGeneticCode.Map()) {
+		t.Errorf("Map: standard table differs from GeneticCode")
+	}
+}
+
+func TestGeneticCodeTable(t *testing.T) {
+	mito := GeneticCodes[2]
+	if exp := "Vertebrate Mitochondrial"; exp != mito.Name {
+		t.Errorf("Name: expected %#v, actual %#v", exp, mito.Name)
+	}
+	if exp := "MW*"; exp != mito.Translate("ATATGAAGA") {
+		t.Errorf("Translate: expected %#v, actual %#v", exp, mito.Translate("ATATGAAGA"))
+	}
+	if exp := []string{"TAA", "TAG", "AGA", "AGG"}; !reflect.DeepEqual(exp, mito.StopCodons()) {
+		t.Errorf("StopCodons: expected %#v, actual %#v", exp, mito.StopCodons())
+	}
+	if exp := []string{"TTG", "CTG", "ATG"}; !reflect.DeepEqual(exp, StandardGeneticCode.StartCodons()) {
+		t.Errorf("StartCodons: expected %#v, actual %#v", exp, StandardGeneticCode.StartCodons())
+	}
+	if !mito.IsStart("ATA") || mito.IsStart("TTG") || !mito.IsStop("AGG") || mito.IsStop("TGA") {
+		t.Errorf("IsStart/IsStop: unexpected result")
+	}
+	if exp := "Q-X"; exp != GeneticCodes[6].Translate("TAA---NNN") {
+		t.Errorf("Translate: expected %#v, actual %#v", exp, GeneticCodes[6].Translate("TAA---NNN"))
+	}
+	if _, err := TranslateWithCode("ATG", 99); err == nil {
+		t.Errorf("TranslateWithCode: expected error for unknown table")
+	}
+}
+
+func TestCodonSequence_SetGeneticCode(t *testing.T) {
+	s := NewCodonSequence("a", "", "ATGTGAAGA")
+	if exp := "M*R"; exp != s.Prot() {
+		t.Errorf("Prot: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if err := s.SetGeneticCode(2); err != nil {
+		t.Fatalf("SetGeneticCode: unexpected error %v", err)
+	}
+	if exp := "MW*"; exp != s.Prot() {
+		t.Errorf("SetGeneticCode: expected %#v, actual %#v", exp, s.Prot())
+	}
+	s.SetSequence("AGGTGG")
+	if exp := "*W"; exp != s.Prot() {
+		t.Errorf("SetSequence: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if err := s.SetGeneticCode(8); err == nil {
+		t.Errorf("SetGeneticCode: expected error for unknown table")
+	}
+	if s.GeneticCode().ID != 2 {
+		t.Errorf("GeneticCode: expected %d, actual %d", 2, s.GeneticCode().ID)
+	}
+}
+
+func TestParseGeneticCodes(t *testing.T) {
+	data := `--**************************************************************
+-- custom table
+Genetic-code-table ::= {
+ {
+  name "Custom code with a
+        long name" ,
+  name "CC1" ,
+  id 100 ,
+  ncbieaa  "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+  sncbieaa "---M------**--*----M---------------M----------------------------"
+  -- Base1  TTTTTTTTTTTTTTTTCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAAGGGGGGGGGGGGGGGG
+ }
+}
+`
+	tables, err := ParseGeneticCodes(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseGeneticCodes: unexpected error %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("ParseGeneticCodes: expected %d tables, actual %d", 1, len(tables))
+	}
+	if exp := "Custom code with a long name"; exp != tables[0].Name || tables[0].ShortName != "CC1" || tables[0].ID != 100 {
+		t.Errorf("ParseGeneticCodes: unexpected table %#v", tables[0])
+	}
+	RegisterGeneticCode(tables[0])
+	defer delete(GeneticCodes, 100)
+	if p, err := TranslateWithCode("ATGTAA", 100); err != nil || p != "M*" {
+		t.Errorf("TranslateWithCode: expected %#v, actual %#v", "M*", p)
+	}
+	for _, data := range []string{
+		"{ { id 1 , ncbieaa \"FF\", sncbieaa \"--\" } }",
+		"{ { name \"x\" , ncbieaa \"FF",
+		"{ { id x } }",
+	} {
+		if _, err := ParseGeneticCodes(strings.NewReader(data)); err == nil {
+			t.Errorf("ParseGeneticCodes: expected error for %#v", data)
+		}
+	}
+}
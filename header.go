@@ -0,0 +1,304 @@
+package gofasta
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// HeaderField is a key and value of a structured FASTA header.
+type HeaderField struct {
+	Key   string
+	Value string
+}
+
+// Header is structured metadata parsed from a FASTA header line. Fields keep
+// the order in which they appear so that a header can be formatted back
+// without reordering its contents.
+type Header []HeaderField
+
+// Get returns the value of the field with the given key and whether it was
+// found.
+func (h Header) Get(key string) (string, bool) {
+	for _, f := range h {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set changes the value of the field with the given key, or appends a new
+// field if the key is not present.
+func (h *Header) Set(key, value string) {
+	for i, f := range *h {
+		if f.Key == key {
+			(*h)[i].Value = value
+			return
+		}
+	}
+	*h = append(*h, HeaderField{key, value})
+}
+
+// Delete removes the field with the given key.
+func (h *Header) Delete(key string) {
+	for i, f := range *h {
+		if f.Key == key {
+			*h = append((*h)[:i], (*h)[i+1:]...)
+			return
+		}
+	}
+}
+
+// HeaderParser converts between a FASTA header line, without the leading
+// '>', and structured metadata. Parse and Format are inverses of each other
+// for headers that follow the convention of the parser.
+type HeaderParser interface {
+	Parse(line string) (Header, error)
+	Format(h Header) string
+}
+
+// ParseHeader parses the header of a sequence, that is its ID and
+// description joined by a space, using the given parser.
+func ParseHeader(s Sequence, p HeaderParser) (Header, error) {
+	line := s.ID()
+	if len(s.Description()) > 0 {
+		line += " " + s.Description()
+	}
+	h, err := p.Parse(line)
+	if err != nil {
+		return nil, fmt.Errorf("parsing header of %q: %w", s.ID(), err)
+	}
+	return h, nil
+}
+
+// SetHeader formats the header using the given parser and stores it as the
+// ID and description of the sequence, splitting it at the first space.
+func SetHeader(s Sequence, p HeaderParser, h Header) {
+	name, desc := parseFastaHeader(">" + p.Format(h))
+	s.SetID(name)
+	s.SetDescription(desc)
+}
+
+// formatFields appends the fields of h whose keys are not in skip to parts,
+// formatting each field using format.
+func formatFields(parts []string, h Header, skip []string, format func(HeaderField) string) []string {
+	for _, f := range h {
+		skipped := false
+		for _, key := range skip {
+			if f.Key == key {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			parts = append(parts, format(f))
+		}
+	}
+	return parts
+}
+
+// uniProtTag matches the start of a UniProt tag such as " OS=".
+var uniProtTag = regexp.MustCompile(`(?:^|\s)([A-Z]{2})=`)
+
+// UniProtHeader parses UniProtKB headers such as
+//
+//	sp|P04637|P53_HUMAN Cellular tumor antigen p53 OS=Homo sapiens OX=9606 GN=TP53 PE=1 SV=4
+//
+// The keys "db", "accession", "entry" and "name" hold the database (sp or
+// tr), the accession, the entry name and the protein name. Tags such as OS,
+// OX, GN, PE and SV are stored under their two-letter key.
+type UniProtHeader struct{}
+
+// Parse implements HeaderParser.
+func (UniProtHeader) Parse(line string) (Header, error) {
+	name, desc := parseFastaHeader(">" + line)
+	ids := strings.Split(name, "|")
+	if len(ids) != 3 || (ids[0] != "sp" && ids[0] != "tr") {
+		return nil, fmt.Errorf("not a UniProt header: %q", line)
+	}
+	h := Header{{"db", ids[0]}, {"accession", ids[1]}, {"entry", ids[2]}}
+	matches := uniProtTag.FindAllStringSubmatchIndex(desc, -1)
+	end := len(desc)
+	if len(matches) > 0 {
+		end = matches[0][0]
+	}
+	h.Set("name", strings.TrimSpace(desc[:end]))
+	for i, m := range matches {
+		end := len(desc)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		h.Set(desc[m[2]:m[3]], strings.TrimSpace(desc[m[1]:end]))
+	}
+	return h, nil
+}
+
+// Format implements HeaderParser.
+func (UniProtHeader) Format(h Header) string {
+	db, _ := h.Get("db")
+	accession, _ := h.Get("accession")
+	entry, _ := h.Get("entry")
+	parts := []string{db + "|" + accession + "|" + entry}
+	if name, _ := h.Get("name"); len(name) > 0 {
+		parts = append(parts, name)
+	}
+	parts = formatFields(parts, h, []string{"db", "accession", "entry", "name"}, func(f HeaderField) string {
+		return f.Key + "=" + f.Value
+	})
+	return strings.Join(parts, " ")
+}
+
+// ncbiDatabases lists the database identifiers of NCBI FASTA ID strings.
+var ncbiDatabases = map[string]bool{
+	"gi": true, "ref": true, "gb": true, "emb": true, "dbj": true,
+	"sp": true, "tr": true, "pdb": true, "pir": true, "prf": true,
+	"lcl": true, "gnl": true, "bbs": true, "bbm": true, "pat": true,
+	"gpp": true, "nat": true, "tpg": true, "tpe": true, "tpd": true,
+}
+
+// NCBIHeader parses NCBI ID strings such as
+//
+//	gi|1234|ref|NM_000546.5| Homo sapiens tumor protein p53 (TP53), mRNA
+//
+// Each database identifier, such as "gi" or "ref", is used as a key for the
+// values that follow it. Databases with several values, such as
+// "pdb|1ABC|A", keep them joined by '|'. The free text after the ID is
+// stored under the key "description".
+type NCBIHeader struct{}
+
+// Parse implements HeaderParser.
+func (NCBIHeader) Parse(line string) (Header, error) {
+	name, desc := parseFastaHeader(">" + line)
+	tokens := strings.Split(strings.TrimSuffix(name, "|"), "|")
+	if !ncbiDatabases[tokens[0]] || len(tokens) < 2 {
+		return nil, fmt.Errorf("not an NCBI header: %q", line)
+	}
+	var h Header
+	for _, token := range tokens {
+		if ncbiDatabases[token] {
+			h = append(h, HeaderField{Key: token})
+			continue
+		}
+		last := &h[len(h)-1]
+		if len(last.Value) > 0 {
+			last.Value += "|"
+		}
+		last.Value += token
+	}
+	if len(desc) > 0 {
+		h = append(h, HeaderField{"description", desc})
+	}
+	return h, nil
+}
+
+// Format implements HeaderParser.
+func (NCBIHeader) Format(h Header) string {
+	parts := formatFields(nil, h, []string{"description"}, func(f HeaderField) string {
+		return f.Key + "|" + f.Value
+	})
+	line := strings.Join(parts, "|") + "|"
+	if desc, _ := h.Get("description"); len(desc) > 0 {
+		line += " " + desc
+	}
+	return line
+}
+
+// GISAIDHeader parses virus names as used by GISAID and influenza
+// databases, optionally followed by '|'-separated fields, such as
+//
+//	hCoV-19/South Africa/NHLS-UCT-GS-0001/2020|EPI_ISL_678615|2020-10-15
+//
+// The virus name is split into the keys "virus", "country", "strain" and
+// "year". The strain holds every part between the country and the year. A
+// field starting with "EPI_" is stored under "accession", any other field
+// under "date".
+type GISAIDHeader struct{}
+
+// Parse implements HeaderParser.
+func (GISAIDHeader) Parse(line string) (Header, error) {
+	fields := strings.Split(strings.TrimSpace(line), "|")
+	name := strings.Split(fields[0], "/")
+	if len(name) < 4 {
+		return nil, fmt.Errorf("not a virus/country/strain/year header: %q", line)
+	}
+	h := Header{
+		{"virus", name[0]},
+		{"country", name[1]},
+		{"strain", strings.Join(name[2:len(name)-1], "/")},
+		{"year", name[len(name)-1]},
+	}
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "EPI_") {
+			h.Set("accession", field)
+		} else {
+			h.Set("date", field)
+		}
+	}
+	return h, nil
+}
+
+// Format implements HeaderParser.
+func (GISAIDHeader) Format(h Header) string {
+	var name []string
+	for _, key := range []string{"virus", "country", "strain", "year"} {
+		value, _ := h.Get(key)
+		name = append(name, value)
+	}
+	line := strings.Join(name, "/")
+	for _, key := range []string{"accession", "date"} {
+		if value, ok := h.Get(key); ok {
+			line += "|" + value
+		}
+	}
+	return line
+}
+
+// KeyValueHeader parses headers whose description is a list of key=value
+// pairs, such as
+//
+//	seq1 country=Japan;host=Homo sapiens;date=2020-03-01
+//
+// The ID is stored under the key "id". Separator is the string between
+// pairs and defaults to ";". Spaces around pairs are ignored.
+type KeyValueHeader struct {
+	Separator string
+}
+
+// separator returns the separator between pairs.
+func (p KeyValueHeader) separator() string {
+	if len(p.Separator) == 0 {
+		return ";"
+	}
+	return p.Separator
+}
+
+// Parse implements HeaderParser.
+func (p KeyValueHeader) Parse(line string) (Header, error) {
+	name, desc := parseFastaHeader(">" + line)
+	h := Header{{"id", name}}
+	for _, pair := range strings.Split(desc, p.separator()) {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		h.Set(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+	return h, nil
+}
+
+// Format implements HeaderParser.
+func (p KeyValueHeader) Format(h Header) string {
+	id, _ := h.Get("id")
+	pairs := formatFields(nil, h, []string{"id"}, func(f HeaderField) string {
+		return f.Key + "=" + f.Value
+	})
+	if len(pairs) == 0 {
+		return id
+	}
+	return id + " " + strings.Join(pairs, p.separator())
+}
@@ -0,0 +1,113 @@
+package gofasta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeader_SetDelete(t *testing.T) {
+	h := Header{{"a", "1"}, {"b", "2"}}
+	h.Set("a", "3")
+	h.Set("c", "4")
+	h.Delete("b")
+	exp := Header{{"a", "3"}, {"c", "4"}}
+	if !reflect.DeepEqual(exp, h) {
+		t.Errorf("Header: expected %#v, actual %#v", exp, h)
+	}
+	if v, ok := h.Get("c"); !ok || v != "4" {
+		t.Errorf("Get: expected %#v, actual %#v", "4", v)
+	}
+	if _, ok := h.Get("b"); ok {
+		t.Errorf("Get: expected missing key")
+	}
+}
+
+func TestHeaderParsers(t *testing.T) {
+	cases := []struct {
+		parser HeaderParser
+		line   string
+		exp    Header
+	}{
+		{
+			UniProtHeader{},
+			"sp|P04637|P53_HUMAN Cellular tumor antigen p53 OS=Homo sapiens OX=9606 GN=TP53 PE=1 SV=4",
+			Header{
+				{"db", "sp"}, {"accession", "P04637"}, {"entry", "P53_HUMAN"},
+				{"name", "Cellular tumor antigen p53"}, {"OS", "Homo sapiens"},
+				{"OX", "9606"}, {"GN", "TP53"}, {"PE", "1"}, {"SV", "4"},
+			},
+		},
+		{
+			NCBIHeader{},
+			"gi|1234|ref|NM_000546.5| Homo sapiens tumor protein p53 (TP53), mRNA",
+			Header{
+				{"gi", "1234"}, {"ref", "NM_000546.5"},
+				{"description", "Homo sapiens tumor protein p53 (TP53), mRNA"},
+			},
+		},
+		{
+			NCBIHeader{},
+			"gi|5678|pdb|1ABC|A|",
+			Header{{"gi", "5678"}, {"pdb", "1ABC|A"}},
+		},
+		{
+			GISAIDHeader{},
+			"hCoV-19/South Africa/NHLS-UCT-GS-0001/2020|EPI_ISL_678615|2020-10-15",
+			Header{
+				{"virus", "hCoV-19"}, {"country", "South Africa"},
+				{"strain", "NHLS-UCT-GS-0001"}, {"year", "2020"},
+				{"accession", "EPI_ISL_678615"}, {"date", "2020-10-15"},
+			},
+		},
+		{
+			KeyValueHeader{},
+			"seq1 country=Japan;host=Homo sapiens;date=2020-03-01",
+			Header{{"id", "seq1"}, {"country", "Japan"}, {"host", "Homo sapiens"}, {"date", "2020-03-01"}},
+		},
+	}
+	for _, c := range cases {
+		h, err := c.parser.Parse(c.line)
+		if err != nil {
+			t.Fatalf("Parse: unexpected error %v", err)
+		}
+		if !reflect.DeepEqual(c.exp, h) {
+			t.Errorf("Parse: expected %#v, actual %#v", c.exp, h)
+		}
+		if line := c.parser.Format(h); line != c.line {
+			t.Errorf("Format: expected %#v, actual %#v", c.line, line)
+		}
+	}
+}
+
+func TestHeaderParsers_Invalid(t *testing.T) {
+	cases := []struct {
+		parser HeaderParser
+		line   string
+	}{
+		{UniProtHeader{}, "P04637 some protein"},
+		{NCBIHeader{}, "abc|1234|"},
+		{GISAIDHeader{}, "hCoV-19/England/2020"},
+		{KeyValueHeader{}, "seq1 country"},
+	}
+	for _, c := range cases {
+		if _, err := c.parser.Parse(c.line); err == nil {
+			t.Errorf("Parse: expected error for %#v", c.line)
+		}
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	s := NewCharSequence("seq1", "host=Human; country=Japan", "ACGT")
+	p := KeyValueHeader{}
+	h, err := ParseHeader(s, p)
+	if err != nil {
+		t.Fatalf("ParseHeader: unexpected error %v", err)
+	}
+	h.Set("country", "Viet Nam")
+	h.Delete("host")
+	h.Set("id", "seq2")
+	SetHeader(s, p, h)
+	if s.ID() != "seq2" || s.Description() != "country=Viet Nam" {
+		t.Errorf("SetHeader: expected %#v %#v, actual %#v %#v", "seq2", "country=Viet Nam", s.ID(), s.Description())
+	}
+}
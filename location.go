@@ -0,0 +1,255 @@
+package gofasta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LocationKind identifies the type of a feature location.
+type LocationKind int
+
+// Kinds of feature locations used in GenBank and EMBL feature tables.
+const (
+	// LocationRange is a single base (467) or a range of bases (340..565).
+	LocationRange LocationKind = iota
+	// LocationBetween is a site between two adjacent bases (123^124).
+	LocationBetween
+	// LocationWithin is a single unknown base within a range (102.110).
+	LocationWithin
+	// LocationComplement is the reverse complement of its single part.
+	LocationComplement
+	// LocationJoin joins its parts into a contiguous sequence.
+	LocationJoin
+	// LocationOrder lists its parts without implying that they are joined.
+	LocationOrder
+)
+
+// Location is a parsed feature location, such as
+// "complement(join(<1..206,3300..>4000))". Start and End are 1-based and
+// inclusive. FuzzyStart and FuzzyEnd mark partial ends written as '<' and
+// '>'. Accession is set for ranges that refer to another record, such as
+// "J00194.1:100..202". Complement, join and order locations hold their
+// contents in Parts.
+type Location struct {
+	Kind       LocationKind
+	Start, End int
+	FuzzyStart bool
+	FuzzyEnd   bool
+	Accession  string
+	Parts      []*Location
+}
+
+// ParseLocation parses a feature location string.
+func ParseLocation(s string) (*Location, error) {
+	p := &locationParser{s: strings.Join(strings.Fields(s), "")}
+	loc, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("invalid location %q: unexpected %q", s, p.s[p.pos:])
+	}
+	return loc, nil
+}
+
+type locationParser struct {
+	s   string
+	pos int
+}
+
+func (p *locationParser) parse() (*Location, error) {
+	for _, op := range []struct {
+		name string
+		kind LocationKind
+	}{
+		{"complement(", LocationComplement},
+		{"join(", LocationJoin},
+		{"order(", LocationOrder},
+	} {
+		if !strings.HasPrefix(p.s[p.pos:], op.name) {
+			continue
+		}
+		p.pos += len(op.name)
+		loc := &Location{Kind: op.kind}
+		for {
+			part, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			loc.Parts = append(loc.Parts, part)
+			if p.pos < len(p.s) && p.s[p.pos] == ',' && op.kind != LocationComplement {
+				p.pos++
+				continue
+			}
+			break
+		}
+		if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+			return nil, fmt.Errorf("invalid location %q: missing ')'", p.s)
+		}
+		p.pos++
+		return loc, nil
+	}
+	return p.parseRange()
+}
+
+func (p *locationParser) parseRange() (*Location, error) {
+	end := strings.IndexAny(p.s[p.pos:], ",)")
+	if end < 0 {
+		end = len(p.s)
+	} else {
+		end += p.pos
+	}
+	token := p.s[p.pos:end]
+	p.pos = end
+
+	loc := &Location{Kind: LocationRange}
+	if i := strings.LastIndex(token, ":"); i >= 0 {
+		loc.Accession, token = token[:i], token[i+1:]
+	}
+	var first, second string
+	switch {
+	case strings.Contains(token, ".."):
+		parts := strings.SplitN(token, "..", 2)
+		first, second = parts[0], parts[1]
+	case strings.Contains(token, "^"):
+		parts := strings.SplitN(token, "^", 2)
+		first, second = parts[0], parts[1]
+		loc.Kind = LocationBetween
+	case strings.Contains(token, "."):
+		parts := strings.SplitN(token, ".", 2)
+		first, second = parts[0], parts[1]
+		loc.Kind = LocationWithin
+	default:
+		if strings.HasPrefix(token, ">") {
+			loc.FuzzyEnd = true
+			token = token[1:]
+		}
+		first, second = token, token
+	}
+	var err error
+	if strings.HasPrefix(first, "<") {
+		loc.FuzzyStart = true
+		first = first[1:]
+		if len(second) > 0 && second[0] == '<' {
+			second = second[1:]
+		}
+	}
+	if strings.HasPrefix(second, ">") {
+		loc.FuzzyEnd = true
+		second = second[1:]
+	}
+	if loc.Start, err = strconv.Atoi(first); err != nil {
+		return nil, fmt.Errorf("invalid location %q", token)
+	}
+	if loc.End, err = strconv.Atoi(second); err != nil {
+		return nil, fmt.Errorf("invalid location %q", token)
+	}
+	if loc.Start < 1 || loc.End < loc.Start {
+		return nil, fmt.Errorf("invalid location %q", token)
+	}
+	return loc, nil
+}
+
+func (l *Location) String() string {
+	switch l.Kind {
+	case LocationComplement, LocationJoin, LocationOrder:
+		name := map[LocationKind]string{
+			LocationComplement: "complement",
+			LocationJoin:       "join",
+			LocationOrder:      "order",
+		}[l.Kind]
+		parts := make([]string, len(l.Parts))
+		for i, part := range l.Parts {
+			parts[i] = part.String()
+		}
+		return name + "(" + strings.Join(parts, ",") + ")"
+	}
+	var s string
+	if len(l.Accession) > 0 {
+		s = l.Accession + ":"
+	}
+	start, end := strconv.Itoa(l.Start), strconv.Itoa(l.End)
+	switch l.Kind {
+	case LocationBetween:
+		return s + start + "^" + end
+	case LocationWithin:
+		return s + start + "." + end
+	}
+	if l.FuzzyStart {
+		start = "<" + start
+	}
+	if l.FuzzyEnd {
+		end = ">" + end
+	}
+	if l.Start == l.End {
+		if l.FuzzyEnd {
+			return s + end
+		}
+		return s + start
+	}
+	return s + start + ".." + end
+}
+
+// IsComplement tells whether the location is on the reverse strand.
+func (l *Location) IsComplement() bool {
+	if l.Kind == LocationComplement {
+		return !l.Parts[0].IsComplement()
+	}
+	if l.Kind == LocationJoin || l.Kind == LocationOrder {
+		for _, part := range l.Parts {
+			if !part.IsComplement() {
+				return false
+			}
+		}
+		return len(l.Parts) > 0
+	}
+	return false
+}
+
+// IsPartial tells whether any end of the location is fuzzy.
+func (l *Location) IsPartial() bool {
+	if l.FuzzyStart || l.FuzzyEnd {
+		return true
+	}
+	for _, part := range l.Parts {
+		if part.IsPartial() {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract returns the part of seq described by the location. Parts of join
+// and order locations are concatenated in the given order and complement
+// locations are reverse complemented. Locations referring to other records
+// and between-base sites cannot be extracted.
+func (l *Location) Extract(seq string) (string, error) {
+	switch l.Kind {
+	case LocationComplement:
+		s, err := l.Parts[0].Extract(seq)
+		if err != nil {
+			return "", err
+		}
+		return reverseComplement(s), nil
+	case LocationJoin, LocationOrder:
+		var buff strings.Builder
+		for _, part := range l.Parts {
+			s, err := part.Extract(seq)
+			if err != nil {
+				return "", err
+			}
+			buff.WriteString(s)
+		}
+		return buff.String(), nil
+	case LocationBetween, LocationWithin:
+		return "", fmt.Errorf("cannot extract location %s", l)
+	}
+	if len(l.Accession) > 0 {
+		return "", fmt.Errorf("cannot extract location %s in another record", l)
+	}
+	if l.End > len(seq) {
+		return "", fmt.Errorf("location %s is outside of sequence of length %d", l, len(seq))
+	}
+	return seq[l.Start-1 : l.End], nil
+}
@@ -0,0 +1,85 @@
+package gofasta
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	cases := []string{
+		"467",
+		"340..565",
+		"<345..500",
+		"<1..>888",
+		"102.110",
+		"123^124",
+		"J00194.1:100..202",
+		"join(12..78,134..202)",
+		"complement(34..126)",
+		"complement(join(2691..4571,4918..5163))",
+		"join(complement(4918..5163),complement(2691..4571))",
+		"order(1..10,20..30)",
+	}
+	for _, s := range cases {
+		loc, err := ParseLocation(s)
+		if err != nil {
+			t.Errorf("ParseLocation(%q): unexpected error %v", s, err)
+			continue
+		}
+		if loc.String() != s {
+			t.Errorf("ParseLocation(%q): expected String %#v, actual %#v", s, s, loc.String())
+		}
+	}
+	for _, s := range []string{"", "join(1..5", "5..2", "x..5", "complement(1..2,3..4)"} {
+		if _, err := ParseLocation(s); err == nil {
+			t.Errorf("ParseLocation(%q): expected error", s)
+		}
+	}
+}
+
+func TestLocation_Extract(t *testing.T) {
+	seq := "AACCGGTTRY"
+	cases := map[string]string{
+		"3..6":                           "CCGG",
+		"join(1..2,5..6)":                "AAGG",
+		"complement(7..10)":              "RYAA",
+		"complement(join(1..2,9..10))":   "RYTT",
+		"join(complement(9..10),1..1)":   "RYA",
+		"complement(<1..>2)":             "TT",
+		"order(complement(1..1),10..10)": "TY",
+	}
+	for s, exp := range cases {
+		loc, err := ParseLocation(s)
+		if err != nil {
+			t.Fatalf("ParseLocation(%q): unexpected error %v", s, err)
+		}
+		actual, err := loc.Extract(seq)
+		if err != nil || actual != exp {
+			t.Errorf("Extract(%q): expected %#v, actual %#v %v", s, exp, actual, err)
+		}
+	}
+	for _, s := range []string{"5..20", "J00194.1:1..2", "2^3"} {
+		loc, _ := ParseLocation(s)
+		if _, err := loc.Extract(seq); err == nil {
+			t.Errorf("Extract(%q): expected error", s)
+		}
+	}
+	// complement locations are always complemented as DNA
+	loc, _ := ParseLocation("complement(1..4)")
+	if actual, _ := loc.Extract("AAUG"); actual != "CATT" {
+		t.Errorf("Extract(%q): expected %#v, actual %#v", "AAUG", "CATT", actual)
+	}
+}
+
+func TestLocation_IsComplement(t *testing.T) {
+	cases := map[string]bool{
+		"1..10":                          false,
+		"complement(1..10)":              true,
+		"complement(join(1..10,20..30))": true,
+		"join(complement(1..10),complement(20..30))": true,
+		"join(complement(1..10),20..30)":             false,
+	}
+	for s, exp := range cases {
+		loc, _ := ParseLocation(s)
+		if loc.IsComplement() != exp {
+			t.Errorf("IsComplement(%q): expected %v", s, exp)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MAFRow describes the source of an aligned sequence in a MAF block.
+// Start is 0-based and refers to the strand of the row, so for rows on the
+// '-' strand it counts from the end of the source sequence.
+type MAFRow struct {
+	Src     string
+	Start   int
+	Size    int
+	Strand  byte
+	SrcSize int
+}
+
+// Species returns the part of the source name before the first '.', which
+// by convention is the genome assembly, such as "hg38".
+func (row MAFRow) Species() string {
+	return strings.SplitN(row.Src, ".", 2)[0]
+}
+
+// Chrom returns the part of the source name after the first '.', such as
+// "chr7". If the name has no '.', the whole name is returned.
+func (row MAFRow) Chrom() string {
+	splitted := strings.SplitN(row.Src, ".", 2)
+	return splitted[len(splitted)-1]
+}
+
+// ForwardStart returns the 0-based start of the row on the '+' strand of the
+// source sequence.
+func (row MAFRow) ForwardStart() int {
+	if row.Strand == '-' {
+		return row.SrcSize - row.Start - row.Size
+	}
+	return row.Start
+}
+
+// MAFBlock is an alignment block of a MAF file. Rows[i] describes the
+// source of Alignment[i], whose ID is the source name. Attributes holds the
+// key=value pairs of the "a" line, such as score.
+type MAFBlock struct {
+	Alignment  Alignment
+	Rows       []MAFRow
+	Attributes map[string]string
+}
+
+// Row returns the index of the first row whose source name is src, or
+// whose species is src if src contains no '.'. It returns -1 if there is no
+// such row.
+func (b *MAFBlock) Row(src string) int {
+	for i, row := range b.Rows {
+		if row.Src == src || (!strings.Contains(src, ".") && row.Species() == src) {
+			return i
+		}
+	}
+	return -1
+}
+
+// MAFReader reads MAF alignment blocks one at a time from an io.Reader,
+// such as UCSC multiz whole-genome alignments. Only "a" and "s" lines are
+// interpreted; "i", "e" and "q" lines and comments are skipped.
+type MAFReader struct {
+	scanner *bufio.Scanner
+	line    int
+
+	block *MAFBlock
+	next  *MAFBlock
+	err   error
+	done  bool
+}
+
+// NewMAFReader creates a new MAFReader that reads from r.
+func NewMAFReader(r io.Reader) *MAFReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	return &MAFReader{scanner: scanner}
+}
+
+// Next advances the reader to the next block, which will then be available
+// through Block. It returns false when there are no more blocks or an error
+// occurred. After Next returns false, Err returns the error that occurred,
+// if any.
+func (r *MAFReader) Next() bool {
+	r.block = nil
+	if r.done {
+		return false
+	}
+	block := r.next
+	r.next = nil
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimRight(r.scanner.Text(), "\r")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			if block != nil {
+				r.block = block
+				return true
+			}
+		case strings.HasPrefix(line, "#"):
+			// header or comment line
+		case fields[0] == "a" && block != nil:
+			// a block that is not followed by a blank line ends at the next
+			// "a" line, which starts the block returned by the next call
+			r.next = newMAFBlock(fields)
+			r.block = block
+			return true
+		case fields[0] == "a":
+			block = newMAFBlock(fields)
+		case block == nil:
+			r.fail(&ParseError{Line: r.line, Reason: "line outside of an alignment block"})
+			return false
+		case fields[0] == "s":
+			if err := r.addRow(block, fields); err != nil {
+				r.fail(err)
+				return false
+			}
+		}
+	}
+	r.done = true
+	if err := r.scanner.Err(); err != nil {
+		r.err = &ParseError{Line: r.line, Reason: "cannot read input", Err: err}
+		return false
+	}
+	r.block = block
+	return block != nil
+}
+
+// newMAFBlock creates an empty block from the fields of an "a" line.
+func newMAFBlock(fields []string) *MAFBlock {
+	block := &MAFBlock{Attributes: make(map[string]string)}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) == 2 {
+			block.Attributes[kv[0]] = kv[1]
+		}
+	}
+	return block
+}
+
+// addRow parses an "s" line and appends it to the block.
+func (r *MAFReader) addRow(block *MAFBlock, fields []string) error {
+	if len(fields) != 7 {
+		return &ParseError{Line: r.line, Reason: "expected 7 fields in 's' line"}
+	}
+	row := MAFRow{Src: fields[1]}
+	var err error
+	if row.Start, err = strconv.Atoi(fields[2]); err != nil {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: "invalid start", Err: err}
+	}
+	if row.Size, err = strconv.Atoi(fields[3]); err != nil {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: "invalid size", Err: err}
+	}
+	if fields[4] != "+" && fields[4] != "-" {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: fmt.Sprintf("invalid strand %q", fields[4])}
+	}
+	row.Strand = fields[4][0]
+	if row.SrcSize, err = strconv.Atoi(fields[5]); err != nil {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: "invalid source size", Err: err}
+	}
+	text := fields[6]
+	if len(block.Alignment) > 0 && len(text) != len(block.Alignment[0].Sequence()) {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: "aligned sequence length differs from the rest of the block"}
+	}
+	if n := len(text) - strings.Count(text, "-"); n != row.Size {
+		return &ParseError{Line: r.line, ID: row.Src, Reason: fmt.Sprintf("size is %d but sequence has %d bases", row.Size, n)}
+	}
+	block.Rows = append(block.Rows, row)
+	block.Alignment = append(block.Alignment, NewCharSequence(row.Src, "", text))
+	return nil
+}
+
+// Block returns the most recent block read by a call to Next.
+func (r *MAFReader) Block() *MAFBlock {
+	return r.block
+}
+
+// Err returns the first non-EOF error that was encountered by the reader.
+func (r *MAFReader) Err() error {
+	return r.err
+}
+
+// fail stops the reader and records the error.
+func (r *MAFReader) fail(err error) {
+	r.done = true
+	r.block = nil
+	r.err = err
+}
+
+// ReadMAFFile reads all blocks in a MAF file.
+// Compressed files are decompressed transparently.
+func ReadMAFFile(path string) ([]*MAFBlock, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMAF(file)
+}
+
+// ReadMAF reads all blocks in a MAF-formatted io.Reader stream.
+func ReadMAF(r io.Reader) (blocks []*MAFBlock, err error) {
+	reader := NewMAFReader(r)
+	for reader.Next() {
+		blocks = append(blocks, reader.Block())
+	}
+	if err = reader.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// StitchMAF projects the blocks onto the region from start (inclusive) to
+// end (exclusive) of the reference source ref, such as "hg38.chr7", using
+// 0-based coordinates on the '+' strand. The result has one row per species
+// with exactly one column per reference base; columns where the reference
+// has a gap are dropped. The reference is the first row, followed by the
+// other species in order of first appearance. Rows are named by species.
+//
+// Reference positions not covered by any block are filled with 'N' in the
+// reference row, and species absent from a block are filled with gaps.
+// Blocks with the reference on the '-' strand are reverse-complemented
+// first. If several rows of a block belong to the same species, the first
+// one is used.
+func StitchMAF(blocks []*MAFBlock, ref string, start, end int) (Alignment, error) {
+	if start < 0 || start >= end {
+		return nil, fmt.Errorf("invalid region %s:%d-%d", ref, start+1, end)
+	}
+	refSpecies := MAFRow{Src: ref}.Species()
+	species := []string{refSpecies}
+	rows := map[string][]byte{refSpecies: []byte(strings.Repeat("N", end-start))}
+	for _, block := range blocks {
+		refIdx := -1
+		for i, row := range block.Rows {
+			if row.Src == ref {
+				refIdx = i
+				break
+			}
+		}
+		if refIdx < 0 {
+			continue
+		}
+		refRow := block.Rows[refIdx]
+		fwdStart := refRow.ForwardStart()
+		if fwdStart >= end || fwdStart+refRow.Size <= start {
+			continue
+		}
+		texts := make([]string, len(block.Alignment))
+		for i, s := range block.Alignment {
+			texts[i] = s.Sequence()
+			if refRow.Strand == '-' {
+				texts[i] = reverseComplement(texts[i])
+			}
+		}
+		// map each species to its first row in the block
+		rowOf := make(map[string]int)
+		for i, row := range block.Rows {
+			sp := row.Species()
+			if i == refIdx || sp == refSpecies {
+				continue
+			}
+			if _, ok := rowOf[sp]; ok {
+				continue
+			}
+			rowOf[sp] = i
+			if _, ok := rows[sp]; !ok {
+				species = append(species, sp)
+				rows[sp] = []byte(strings.Repeat("-", end-start))
+			}
+		}
+		pos := fwdStart
+		for col := 0; col < len(texts[refIdx]); col++ {
+			c := texts[refIdx][col]
+			if c == '-' {
+				continue
+			}
+			if pos >= start && pos < end {
+				rows[refSpecies][pos-start] = c
+				for sp, i := range rowOf {
+					rows[sp][pos-start] = texts[i][col]
+				}
+			}
+			pos++
+		}
+	}
+	a := make(Alignment, len(species))
+	for i, sp := range species {
+		a[i] = NewCharSequence(sp, "", string(rows[sp]))
+	}
+	return a, nil
+}
@@ -0,0 +1,101 @@
+package gofasta
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const mafText = `##maf version=1 scoring=tba.v8
+# tba.v8 (((human chimp) baboon) (mouse rat))
+
+a score=23262.0
+s hg18.chr7    10 6 + 100 AC-GTAC
+s panTro1.chr6 20 7 + 200 ACTGTAC
+i panTro1.chr6 N 0 C 0
+s mm4.chr6     30 4 - 300 A--GTA-
+
+a score=5062.0
+s hg18.chr7    18 4 + 100 TT-GG
+s baboon       5  5 + 50  TTAGG
+`
+
+func TestReadMAF(t *testing.T) {
+	blocks, err := ReadMAF(strings.NewReader(mafText))
+	if err != nil {
+		t.Fatalf("ReadMAF: unexpected error %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("ReadMAF: expected %d blocks, actual %d", 2, len(blocks))
+	}
+	b := blocks[0]
+	if b.Attributes["score"] != "23262.0" {
+		t.Errorf("ReadMAF: expected %#v, actual %#v", "23262.0", b.Attributes["score"])
+	}
+	exp := MAFRow{Src: "mm4.chr6", Start: 30, Size: 4, Strand: '-', SrcSize: 300}
+	if !reflect.DeepEqual(exp, b.Rows[2]) {
+		t.Errorf("ReadMAF: expected %#v, actual %#v", exp, b.Rows[2])
+	}
+	if b.Alignment[2].ID() != "mm4.chr6" || b.Alignment[2].Sequence() != "A--GTA-" {
+		t.Errorf("ReadMAF: unexpected row %#v", b.Alignment[2])
+	}
+	if exp.Species() != "mm4" || exp.Chrom() != "chr6" || exp.ForwardStart() != 266 {
+		t.Errorf("MAFRow: unexpected %#v %#v %#v", exp.Species(), exp.Chrom(), exp.ForwardStart())
+	}
+	if i := b.Row("panTro1"); i != 1 {
+		t.Errorf("Row: expected %d, actual %d", 1, i)
+	}
+}
+
+func TestReadMAF_NoBlankLine(t *testing.T) {
+	text := strings.Replace(mafText, "A--GTA-\n\na", "A--GTA-\na", 1)
+	blocks, err := ReadMAF(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ReadMAF: unexpected error %v", err)
+	}
+	if len(blocks) != 2 || len(blocks[0].Rows) != 3 || len(blocks[1].Rows) != 2 {
+		t.Fatalf("ReadMAF: expected blocks of 3 and 2 rows, actual %#v", blocks)
+	}
+	if blocks[1].Attributes["score"] != "5062.0" {
+		t.Errorf("ReadMAF: expected %#v, actual %#v", "5062.0", blocks[1].Attributes["score"])
+	}
+}
+
+func TestReadMAF_Error(t *testing.T) {
+	for _, text := range []string{
+		"s hg18.chr7 10 6 + 100 ACGTAC\n",
+		"a\ns hg18.chr7 10 6 + 100\n",
+		"a\ns hg18.chr7 10 6 x 100 ACGTAC\n",
+		"a\ns hg18.chr7 10 5 + 100 ACGTAC\n",
+		"a\ns hg18.chr7 10 6 + 100 ACGTAC\ns mm4.chr1 1 5 + 100 ACGTA\n",
+	} {
+		if _, err := ReadMAF(strings.NewReader(text)); err == nil {
+			t.Errorf("ReadMAF: expected error for %#v", text)
+		}
+	}
+}
+
+func TestStitchMAF(t *testing.T) {
+	blocks, _ := ReadMAF(strings.NewReader(mafText))
+	a, err := StitchMAF(blocks, "hg18.chr7", 8, 24)
+	if err != nil {
+		t.Fatalf("StitchMAF: unexpected error %v", err)
+	}
+	exp := []struct{ id, seq string }{
+		{"hg18", "NNACGTACNNTTGGNN"},
+		{"panTro1", "--ACGTAC--------"},
+		{"mm4", "--A-GTA---------"},
+		{"baboon", "----------TTGG--"},
+	}
+	if len(a) != len(exp) {
+		t.Fatalf("StitchMAF: expected %d rows, actual %d", len(exp), len(a))
+	}
+	for i, e := range exp {
+		if e.id != a[i].ID() || e.seq != a[i].Sequence() {
+			t.Errorf("StitchMAF: expected %#v %#v, actual %#v %#v", e.id, e.seq, a[i].ID(), a[i].Sequence())
+		}
+	}
+	if _, err := StitchMAF(blocks, "hg18.chr7", 5, 5); err == nil {
+		t.Errorf("StitchMAF: expected error for empty region")
+	}
+}
@@ -0,0 +1,650 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Nexus holds the contents of the DATA or CHARACTERS block and the character
+// sets of the SETS or ASSUMPTIONS block of a NEXUS file.
+//
+// DataType is the value of the DATATYPE format subcommand, such as "DNA",
+// "RNA" or "Protein". Gap, Missing and MatchChar are the single-character
+// symbols used in the matrix. Interleave controls whether the matrix is
+// written in interleaved blocks.
+type Nexus struct {
+	Alignment  Alignment
+	DataType   string
+	Gap        string
+	Missing    string
+	MatchChar  string
+	Interleave bool
+	CharSets   []CharSet
+	Partitions []CharPartition
+}
+
+// CharRange is a range of alignment columns in NEXUS notation. Start and
+// End are 1-based and inclusive. An End of 0 stands for the last column
+// ("."). Step selects every Step-th column, such as 1-.\3 for the first
+// codon positions. A Step of 0 or 1 selects every column.
+type CharRange struct {
+	Start, End, Step int
+}
+
+// CharSet is a named set of alignment columns, as defined by the CHARSET
+// command.
+type CharSet struct {
+	Name   string
+	Ranges []CharRange
+}
+
+// CharPartition divides the alignment columns into named subsets, as
+// defined by the CHARPARTITION command.
+type CharPartition struct {
+	Name    string
+	Subsets []CharSet
+}
+
+// NewNexus creates a Nexus from an alignment using the given data type and
+// the default gap ('-') and missing ('?') symbols.
+func NewNexus(a Alignment, dataType string) *Nexus {
+	return &Nexus{Alignment: a, DataType: dataType, Gap: "-", Missing: "?"}
+}
+
+// Columns returns the 0-based alignment columns in the range for an
+// alignment with nchar columns.
+func (r CharRange) Columns(nchar int) (cols []int) {
+	end := r.End
+	if end == 0 || end > nchar {
+		end = nchar
+	}
+	step := r.Step
+	if step < 1 {
+		step = 1
+	}
+	for i := r.Start; i <= end; i += step {
+		cols = append(cols, i-1)
+	}
+	return
+}
+
+func (r CharRange) String() string {
+	end := "."
+	if r.End > 0 {
+		end = strconv.Itoa(r.End)
+	}
+	var s string
+	if r.End == r.Start {
+		s = strconv.Itoa(r.Start)
+	} else {
+		s = fmt.Sprintf("%d-%s", r.Start, end)
+	}
+	if r.Step > 1 {
+		s += fmt.Sprintf("\\%d", r.Step)
+	}
+	return s
+}
+
+// Columns returns the sorted 0-based alignment columns in the character set
+// for an alignment with nchar columns.
+func (c CharSet) Columns(nchar int) (cols []int) {
+	set := make([]bool, nchar)
+	for _, r := range c.Ranges {
+		for _, i := range r.Columns(nchar) {
+			if i >= 0 && i < nchar {
+				set[i] = true
+			}
+		}
+	}
+	for i, ok := range set {
+		if ok {
+			cols = append(cols, i)
+		}
+	}
+	return
+}
+
+// RangeString returns the ranges of the character set in NEXUS notation.
+func (c CharSet) RangeString() string {
+	parts := make([]string, len(c.Ranges))
+	for i, r := range c.Ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// CharSet returns the character set with the given name.
+// Names are compared case-insensitively, as in NEXUS.
+func (n *Nexus) CharSet(name string) (CharSet, bool) {
+	for _, c := range n.CharSets {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return CharSet{}, false
+}
+
+// ReadNexusFile reads a NEXUS file. Compressed files are decompressed
+// transparently.
+func ReadNexusFile(path string, toCodon bool) (*Nexus, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadNexus(file, toCodon)
+}
+
+// ReadNexus reads the DATA or CHARACTERS block and the SETS or ASSUMPTIONS
+// blocks of a NEXUS-formatted io.Reader stream. Other blocks are skipped.
+// If toCodon is true, sequences are stored as CodonSequence, otherwise as
+// CharSequence.
+func ReadNexus(r io.Reader, toCodon bool) (*Nexus, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := string(b)
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "#NEXUS") {
+		return nil, &ParseError{Line: 1, Reason: "missing #NEXUS header"}
+	}
+	headerLine := 1 + strings.Count(text[:len(text)-len(trimmed)], "\n")
+	commands, err := splitNexusCommands(trimmed[6:], headerLine)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &nexusParser{nexus: &Nexus{Gap: "-", Missing: "?"}}
+	block := ""
+	for _, cmd := range commands {
+		switch {
+		case cmd.name == "begin":
+			block = strings.ToLower(strings.TrimSpace(cmd.body))
+		case cmd.name == "end" || cmd.name == "endblock":
+			block = ""
+		case block == "data" || block == "characters":
+			err = p.dataCommand(cmd)
+		case block == "taxa" && cmd.name == "dimensions":
+			err = p.dimensions(cmd)
+		case block == "sets" || block == "assumptions":
+			err = p.setsCommand(cmd)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = p.buildAlignment(toCodon); err != nil {
+		return nil, err
+	}
+	return p.nexus, nil
+}
+
+type nexusCommand struct {
+	name string
+	body string
+	line int
+}
+
+// splitNexusCommands splits NEXUS text into semicolon-terminated commands,
+// removing comments in square brackets. Line breaks are preserved so that
+// the matrix can be parsed line by line.
+func splitNexusCommands(text string, line int) ([]nexusCommand, error) {
+	var commands []nexusCommand
+	var buff strings.Builder
+	var quote rune
+	depth := 0
+	start := -1
+	for _, c := range text {
+		if c == '\n' {
+			line++
+		}
+		switch {
+		case depth > 0:
+			if c == '[' {
+				depth++
+			} else if c == ']' {
+				depth--
+			}
+			if c == '\n' {
+				buff.WriteRune(c)
+			}
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '[':
+			depth++
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ';':
+			body := buff.String()
+			trimmed := strings.TrimLeftFunc(body, unicode.IsSpace)
+			i := strings.IndexFunc(trimmed, unicode.IsSpace)
+			if i < 0 {
+				i = len(trimmed)
+			}
+			commands = append(commands, nexusCommand{
+				name: strings.ToLower(trimmed[:i]),
+				body: trimmed[i:],
+				line: start,
+			})
+			buff.Reset()
+			start = -1
+			continue
+		}
+		if start < 0 && !unicode.IsSpace(c) {
+			start = line
+		}
+		buff.WriteRune(c)
+	}
+	if depth > 0 {
+		return nil, &ParseError{Line: line, Reason: "unterminated comment"}
+	}
+	if quote != 0 {
+		return nil, &ParseError{Line: line, Reason: "unterminated quoted token"}
+	}
+	if len(strings.TrimSpace(buff.String())) > 0 {
+		return nil, &ParseError{Line: start, Reason: "missing ';' at end of command"}
+	}
+	return commands, nil
+}
+
+// nexusTokens splits a command body into words, quoted words and the
+// punctuation characters '=', ',' and ':'.
+func nexusTokens(s string) (tokens []string) {
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '=' || c == ',' || c == ':':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'' || c == '"':
+			token, n := unquoteNexus(runes[i:])
+			tokens = append(tokens, token)
+			i += n
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("=,:'\"", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		}
+	}
+	return
+}
+
+// unquoteNexus reads a quoted token at the start of runes. A doubled quote
+// inside the token stands for a literal quote. It returns the token and the
+// number of runes consumed.
+func unquoteNexus(runes []rune) (string, int) {
+	quote := runes[0]
+	var buff strings.Builder
+	i := 1
+	for ; i < len(runes); i++ {
+		if runes[i] == quote {
+			if i+1 < len(runes) && runes[i+1] == quote {
+				buff.WriteRune(quote)
+				i++
+				continue
+			}
+			i++
+			break
+		}
+		buff.WriteRune(runes[i])
+	}
+	return buff.String(), i
+}
+
+type nexusParser struct {
+	nexus        *Nexus
+	ntax, nchar  int
+	names        []string
+	seqs         []*strings.Builder
+	lengths      []int
+	matrixLine   int
+	matrixParsed bool
+}
+
+// keyValues parses subcommands of the form key=value or key into a map with
+// lowercase keys.
+func keyValues(tokens []string) map[string]string {
+	values := make(map[string]string)
+	for i := 0; i < len(tokens); i++ {
+		key := strings.ToLower(tokens[i])
+		if i+2 < len(tokens) && tokens[i+1] == "=" {
+			values[key] = tokens[i+2]
+			i += 2
+		} else {
+			values[key] = ""
+		}
+	}
+	return values
+}
+
+func (p *nexusParser) dimensions(cmd nexusCommand) error {
+	for key, value := range keyValues(nexusTokens(cmd.body)) {
+		var err error
+		switch key {
+		case "ntax":
+			p.ntax, err = strconv.Atoi(value)
+		case "nchar":
+			p.nchar, err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return &ParseError{Line: cmd.line, Reason: fmt.Sprintf("invalid %s", strings.ToUpper(key)), Err: err}
+		}
+	}
+	return nil
+}
+
+func (p *nexusParser) dataCommand(cmd nexusCommand) error {
+	switch cmd.name {
+	case "dimensions":
+		return p.dimensions(cmd)
+	case "format":
+		for key, value := range keyValues(nexusTokens(cmd.body)) {
+			switch key {
+			case "datatype":
+				p.nexus.DataType = value
+			case "gap":
+				p.nexus.Gap = value
+			case "missing":
+				p.nexus.Missing = value
+			case "matchchar":
+				p.nexus.MatchChar = value
+			case "interleave":
+				p.nexus.Interleave = value == "" || strings.EqualFold(value, "yes")
+			}
+		}
+	case "matrix":
+		return p.matrix(cmd)
+	}
+	return nil
+}
+
+func (p *nexusParser) matrix(cmd nexusCommand) error {
+	p.matrixParsed = true
+	p.matrixLine = cmd.line
+	index := make(map[string]int)
+	current := -1
+	for i, line := range strings.Split(cmd.body, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		continuation := !p.nexus.Interleave && current >= 0 && p.nchar > 0 && p.lengths[current] < p.nchar
+		if !continuation {
+			var name string
+			runes := []rune(line)
+			if runes[0] == '\'' || runes[0] == '"' {
+				var n int
+				name, n = unquoteNexus(runes)
+				line = string(runes[n:])
+			} else {
+				j := strings.IndexFunc(line, unicode.IsSpace)
+				if j < 0 {
+					j = len(line)
+				}
+				name, line = line[:j], line[j:]
+			}
+			var ok bool
+			if current, ok = index[name]; !ok {
+				current = len(p.names)
+				index[name] = current
+				p.names = append(p.names, name)
+				p.seqs = append(p.seqs, new(strings.Builder))
+				p.lengths = append(p.lengths, 0)
+			}
+		}
+		for _, c := range line {
+			if !unicode.IsSpace(c) {
+				p.seqs[current].WriteRune(c)
+				p.lengths[current]++
+			}
+		}
+		if p.nchar > 0 && p.lengths[current] > p.nchar {
+			return &ParseError{Line: cmd.line + i, ID: p.names[current], Reason: fmt.Sprintf("sequence has more than %d characters", p.nchar)}
+		}
+	}
+	return nil
+}
+
+func (p *nexusParser) buildAlignment(toCodon bool) error {
+	if !p.matrixParsed {
+		return &ParseError{Reason: "missing DATA or CHARACTERS block with MATRIX"}
+	}
+	if p.ntax > 0 && len(p.names) != p.ntax {
+		return &ParseError{Line: p.matrixLine, Reason: fmt.Sprintf("expected %d sequences, found %d", p.ntax, len(p.names))}
+	}
+	var first []rune
+	for i, name := range p.names {
+		seq := p.seqs[i].String()
+		if p.nchar > 0 && p.lengths[i] != p.nchar {
+			return &ParseError{Line: p.matrixLine, ID: name, Reason: fmt.Sprintf("expected %d characters, found %d", p.nchar, p.lengths[i])}
+		}
+		// Replace match characters with the character of the first sequence
+		if i == 0 {
+			first = []rune(seq)
+		} else if match, _ := utf8.DecodeRuneInString(p.nexus.MatchChar); len(p.nexus.MatchChar) > 0 {
+			runes := []rune(seq)
+			for j, c := range runes {
+				if c == match && j < len(first) {
+					runes[j] = first[j]
+				}
+			}
+			seq = string(runes)
+		}
+		s, err := newSequence(name, "", seq, toCodon)
+		if err != nil {
+			return &ParseError{Line: p.matrixLine, ID: name, Reason: "invalid codon sequence", Err: err}
+		}
+		p.nexus.Alignment = append(p.nexus.Alignment, s)
+	}
+	return nil
+}
+
+var nexusRangeSpace = regexp.MustCompile(`\s*([-\\])\s*`)
+
+func (p *nexusParser) setsCommand(cmd nexusCommand) error {
+	if cmd.name != "charset" && cmd.name != "charpartition" {
+		return nil
+	}
+	i := strings.Index(cmd.body, "=")
+	if i < 0 {
+		return &ParseError{Line: cmd.line, Reason: fmt.Sprintf("missing '=' in %s", strings.ToUpper(cmd.name))}
+	}
+	nameTokens := nexusTokens(cmd.body[:i])
+	if len(nameTokens) == 0 {
+		return &ParseError{Line: cmd.line, Reason: fmt.Sprintf("missing %s name", strings.ToUpper(cmd.name))}
+	}
+	// Skip the optional '*' marking the default set
+	name := nameTokens[len(nameTokens)-1]
+	if name == "*" {
+		name = nameTokens[0]
+	} else if nameTokens[0] == "*" {
+		name = nameTokens[1]
+	}
+	body := cmd.body[i+1:]
+
+	if cmd.name == "charset" {
+		ranges, err := p.parseRanges(body)
+		if err != nil {
+			return &ParseError{Line: cmd.line, ID: name, Reason: "invalid CHARSET", Err: err}
+		}
+		p.nexus.CharSets = append(p.nexus.CharSets, CharSet{name, ranges})
+		return nil
+	}
+	partition := CharPartition{Name: name}
+	for _, part := range strings.Split(body, ",") {
+		j := strings.Index(part, ":")
+		if j < 0 {
+			return &ParseError{Line: cmd.line, ID: name, Reason: "missing ':' in CHARPARTITION subset"}
+		}
+		subsetName := strings.TrimSpace(part[:j])
+		if tokens := nexusTokens(part[:j]); len(tokens) > 0 {
+			subsetName = tokens[0]
+		}
+		ranges, err := p.parseRanges(part[j+1:])
+		if err != nil {
+			return &ParseError{Line: cmd.line, ID: name, Reason: "invalid CHARPARTITION", Err: err}
+		}
+		partition.Subsets = append(partition.Subsets, CharSet{subsetName, ranges})
+	}
+	p.nexus.Partitions = append(p.nexus.Partitions, partition)
+	return nil
+}
+
+// parseRanges parses a character set specification such as
+// "1-100 201-.\3 gene1" into ranges. Names of previously defined character
+// sets are replaced by their ranges.
+func (p *nexusParser) parseRanges(spec string) (ranges []CharRange, err error) {
+	spec = nexusRangeSpace.ReplaceAllString(spec, "$1")
+	for _, token := range nexusTokens(spec) {
+		if c, ok := p.nexus.CharSet(token); ok {
+			ranges = append(ranges, c.Ranges...)
+			continue
+		}
+		var r CharRange
+		if i := strings.Index(token, "\\"); i >= 0 {
+			if r.Step, err = strconv.Atoi(token[i+1:]); err != nil {
+				return nil, err
+			}
+			token = token[:i]
+		}
+		bounds := strings.SplitN(token, "-", 2)
+		if r.Start, err = strconv.Atoi(bounds[0]); err != nil {
+			return nil, fmt.Errorf("unknown character set %q", token)
+		}
+		r.End = r.Start
+		if len(bounds) == 2 && bounds[1] == "." {
+			r.End = 0
+		} else if len(bounds) == 2 {
+			if r.End, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return
+}
+
+// quoteNexus quotes a NEXUS word if it contains whitespace or punctuation.
+func quoteNexus(s string) string {
+	if len(s) > 0 && strings.IndexFunc(s, func(c rune) bool {
+		return unicode.IsSpace(c) || strings.ContainsRune("()[]{}/\\,;:=*'\"`+-<>", c)
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// WriteFile saves the NEXUS data to a file.
+func (n *Nexus) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = n.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes the alignment as a DATA block, followed by a SETS block if
+// character sets or partitions are defined. The alignment must be valid,
+// see Alignment.Valid.
+func (n *Nexus) Write(w io.Writer) error {
+	if !n.Alignment.Valid() {
+		return ErrInvalidAlignment
+	}
+	nchar := 0
+	if len(n.Alignment) > 0 {
+		nchar = utf8.RuneCountInString(n.Alignment[0].Sequence())
+	}
+	names := make([]string, len(n.Alignment))
+	nameWidth := 0
+	for i, s := range n.Alignment {
+		names[i] = quoteNexus(s.ID())
+		if l := utf8.RuneCountInString(names[i]); l > nameWidth {
+			nameWidth = l
+		}
+	}
+	dataType := n.DataType
+	if len(dataType) == 0 {
+		dataType = "DNA"
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("#NEXUS\n\nBEGIN DATA;\n")
+	fmt.Fprintf(bw, "\tDIMENSIONS NTAX=%d NCHAR=%d;\n", len(n.Alignment), nchar)
+	fmt.Fprintf(bw, "\tFORMAT DATATYPE=%s", dataType)
+	if len(n.Missing) > 0 {
+		fmt.Fprintf(bw, " MISSING=%s", n.Missing)
+	}
+	if len(n.Gap) > 0 {
+		fmt.Fprintf(bw, " GAP=%s", n.Gap)
+	}
+	if len(n.MatchChar) > 0 {
+		fmt.Fprintf(bw, " MATCHCHAR=%s", n.MatchChar)
+	}
+	if n.Interleave {
+		bw.WriteString(" INTERLEAVE")
+	}
+	bw.WriteString(";\n\tMATRIX\n")
+
+	blockWidth := nchar
+	if n.Interleave {
+		blockWidth = phylipBlockWidth
+	}
+	seqs := make([][]rune, len(n.Alignment))
+	for i, s := range n.Alignment {
+		seqs[i] = []rune(s.Sequence())
+	}
+	for start := 0; start < nchar || start == 0; start += blockWidth {
+		end := start + blockWidth
+		if end > nchar {
+			end = nchar
+		}
+		if start > 0 {
+			bw.WriteByte('\n')
+		}
+		for i := range n.Alignment {
+			fmt.Fprintf(bw, "\t%-*s %s\n", nameWidth, names[i], string(seqs[i][start:end]))
+		}
+		if blockWidth == 0 {
+			break
+		}
+	}
+	bw.WriteString("\t;\nEND;\n")
+
+	if len(n.CharSets) > 0 || len(n.Partitions) > 0 {
+		bw.WriteString("\nBEGIN SETS;\n")
+		for _, c := range n.CharSets {
+			fmt.Fprintf(bw, "\tCHARSET %s = %s;\n", quoteNexus(c.Name), c.RangeString())
+		}
+		for _, p := range n.Partitions {
+			parts := make([]string, len(p.Subsets))
+			for i, c := range p.Subsets {
+				parts[i] = fmt.Sprintf("%s: %s", quoteNexus(c.Name), c.RangeString())
+			}
+			fmt.Fprintf(bw, "\tCHARPARTITION %s = %s;\n", quoteNexus(p.Name), strings.Join(parts, ", "))
+		}
+		bw.WriteString("END;\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,157 @@
+package gofasta
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const nexusInterleaved = `#NEXUS
+[ example file ]
+BEGIN TAXA;
+	DIMENSIONS NTAX=3;
+	TAXLABELS a 'b c' d;
+END;
+
+BEGIN CHARACTERS;
+	DIMENSIONS NCHAR=12;
+	FORMAT DATATYPE=DNA MISSING=? GAP=- MATCHCHAR=. INTERLEAVE;
+	MATRIX
+	a     ATGGCG [first block]
+	'b c' ATG..A
+	d     ATG---
+
+	a     TGGTAA
+	'b c' ......
+	d     TGG?AA
+	;
+END;
+
+BEGIN MRBAYES;
+	lset nst=6;
+END;
+
+BEGIN SETS;
+	CHARSET first = 1-.\3;
+	CHARSET second = 2-12\3;
+	CHARSET coding = first second 3 - 12 \ 3;
+	CHARPARTITION byPos = pos1: 1-.\3, pos2: second, pos3: 3-12\3;
+END;
+`
+
+func TestReadNexus(t *testing.T) {
+	n, err := ReadNexus(strings.NewReader(nexusInterleaved), true)
+	if err != nil {
+		t.Fatalf("ReadNexus: unexpected error %v", err)
+	}
+	if n.DataType != "DNA" || n.Gap != "-" || n.Missing != "?" || !n.Interleave {
+		t.Errorf("ReadNexus: unexpected format %+v", n)
+	}
+	expIDs := []string{"a", "b c", "d"}
+	expSeqs := []string{"ATGGCGTGGTAA", "ATGGCATGGTAA", "ATG---TGG?AA"}
+	if len(n.Alignment) != 3 {
+		t.Fatalf("ReadNexus: expected 3 sequences, actual %d", len(n.Alignment))
+	}
+	for i, s := range n.Alignment {
+		if s.ID() != expIDs[i] || s.Sequence() != expSeqs[i] {
+			t.Errorf("ReadNexus: expected %s %s, actual %s %s", expIDs[i], expSeqs[i], s.ID(), s.Sequence())
+		}
+	}
+	if prot := n.Alignment[0].(*CodonSequence).Prot(); prot != "MAW*" {
+		t.Errorf("ReadNexus: expected %#v, actual %#v", "MAW*", prot)
+	}
+	coding, ok := n.CharSet("CODING")
+	if !ok {
+		t.Fatalf("ReadNexus: expected charset coding")
+	}
+	if exp := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}; !reflect.DeepEqual(exp, coding.Columns(12)) {
+		t.Errorf("CharSet.Columns: expected %v, actual %v", exp, coding.Columns(12))
+	}
+	if len(n.Partitions) != 1 || len(n.Partitions[0].Subsets) != 3 {
+		t.Fatalf("ReadNexus: expected 1 partition with 3 subsets, actual %+v", n.Partitions)
+	}
+	if exp := []int{1, 4, 7, 10}; !reflect.DeepEqual(exp, n.Partitions[0].Subsets[1].Columns(12)) {
+		t.Errorf("CharPartition: expected %v, actual %v", exp, n.Partitions[0].Subsets[1].Columns(12))
+	}
+}
+
+func TestReadNexus_Sequential(t *testing.T) {
+	data := "#nexus\nbegin data;\ndimensions ntax=2 nchar=8;\nformat datatype=protein;\nmatrix\n" +
+		"x MKV\nLLAA\nK\ny MKVLLAAR\n;\nend;\n"
+	n, err := ReadNexus(strings.NewReader(data), false)
+	if err != nil {
+		t.Fatalf("ReadNexus: unexpected error %v", err)
+	}
+	if n.DataType != "protein" || n.Alignment[0].Sequence() != "MKVLLAAK" || n.Alignment[1].ID() != "y" {
+		t.Errorf("ReadNexus: unexpected result %+v", n)
+	}
+}
+
+func TestReadNexus_Errors(t *testing.T) {
+	cases := map[string]int{
+		"BEGIN DATA;\nEND;\n": 1,
+		"#NEXUS\nBEGIN DATA;\nDIMENSIONS NTAX=2 NCHAR=4;\nMATRIX\na ACGT\n;\nEND;\n":      4,
+		"#NEXUS\nBEGIN DATA;\nDIMENSIONS NTAX=1 NCHAR=4;\nMATRIX\n\na ACGTA\n;\nEND;\n":   6,
+		"#NEXUS\nBEGIN DATA;\nDIMENSIONS NTAX=1 NCHAR=4;\nMATRIX\na ACGT\n;\nEND\n":       7,
+		"#NEXUS\nBEGIN DATA;\nMATRIX\na ACGT\n;\nEND;\nBEGIN SETS;\nCHARSET x = y;\nEND;": 8,
+	}
+	for data, line := range cases {
+		_, err := ReadNexus(strings.NewReader(data), false)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ReadNexus: expected *ParseError for %#v, actual %v", data, err)
+		} else if perr.Line != line {
+			t.Errorf("ReadNexus: expected error on line %d, actual %v", line, perr)
+		}
+	}
+}
+
+func TestNexus_Write_RoundTrip(t *testing.T) {
+	n, err := ReadNexus(strings.NewReader(nexusInterleaved), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	if err = n.Write(&buff); err != nil {
+		t.Fatalf("Write: unexpected error %v", err)
+	}
+	actual, err := ReadNexus(&buff, false)
+	if err != nil {
+		t.Fatalf("ReadNexus: unexpected error %v\n%s", err, buff.String())
+	}
+	if n.Alignment.ToFasta() != actual.Alignment.ToFasta() {
+		t.Errorf("Write: alignment mismatch\n%s", actual.Alignment.ToFasta())
+	}
+	if !reflect.DeepEqual(n.CharSets, actual.CharSets) || !reflect.DeepEqual(n.Partitions, actual.Partitions) {
+		t.Errorf("Write: sets mismatch %+v %+v", actual.CharSets, actual.Partitions)
+	}
+}
+
+func TestNexus_Write(t *testing.T) {
+	n := NewNexus(Alignment{
+		NewCharSequence("a", "", "ACGT"),
+		NewCharSequence("b c", "", "AC-T"),
+	}, "DNA")
+	n.CharSets = []CharSet{{"all", []CharRange{{1, 0, 0}}}}
+	var buff bytes.Buffer
+	if err := n.Write(&buff); err != nil {
+		t.Fatalf("Write: unexpected error %v", err)
+	}
+	exp := "#NEXUS\n\nBEGIN DATA;\n" +
+		"\tDIMENSIONS NTAX=2 NCHAR=4;\n" +
+		"\tFORMAT DATATYPE=DNA MISSING=? GAP=-;\n" +
+		"\tMATRIX\n" +
+		"\ta     ACGT\n" +
+		"\t'b c' AC-T\n" +
+		"\t;\nEND;\n\n" +
+		"BEGIN SETS;\n\tCHARSET all = 1-.;\nEND;\n"
+	if exp != buff.String() {
+		t.Errorf("Write: expected\n%s\nactual\n%s", exp, buff.String())
+	}
+	n.Alignment = append(n.Alignment, NewCharSequence("d", "", "A"))
+	if err := n.Write(&buff); err != ErrInvalidAlignment {
+		t.Errorf("Write: expected ErrInvalidAlignment, actual %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package gofasta
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ORF is an open reading frame found by FindORFs. Start and End are 0-based
+// half-open coordinates on the forward strand of the searched sequence and
+// include the stop codon, if any. Frame is the offset of the first codon
+// from the 5' end of the strand. Partial is true if the ORF reaches the end
+// of the sequence without a stop codon. Sequence holds the coding
+// nucleotides of the ORF in the 5' to 3' direction of its strand. Its
+// protein is translated using ResolveCodon, like the search for stop
+// codons, so ambiguous codons encoding a single amino acid are translated.
+type ORF struct {
+	Start    int
+	End      int
+	Strand   byte
+	Frame    int
+	Partial  bool
+	Sequence *CodonSequence
+}
+
+// Length returns the length of the ORF in nucleotides.
+func (o *ORF) Length() int {
+	return o.End - o.Start
+}
+
+// ORFOptions configures FindORFs.
+//
+// Code is the NCBI translation table ID; 0 selects the standard genetic
+// code. ORFs shorter than MinLength nucleotides, including the stop codon,
+// are skipped. If AlternativeStarts is true, every start codon of the
+// table can initiate an ORF, otherwise only ATG. If IncludePartial is true,
+// ORFs that reach the end of the sequence without a stop codon are also
+// reported.
+type ORFOptions struct {
+	Code              int
+	MinLength         int
+	AlternativeStarts bool
+	IncludePartial    bool
+}
+
+// FindORFs scans the six reading frames of the nucleotide sequence for
+// open reading frames, that is stretches starting with a start codon and
+// ending with a stop codon. For each stop codon only the longest ORF,
+// starting at the first start codon after the previous stop codon, is
+// reported. ORFs are sorted by start position and named after the
+// sequence ID with a running number, such as "seq1_1". Their description
+// gives the 1-based coordinates in the style of EMBOSS getorf.
+func (s *CharSequence) FindORFs(opts ORFOptions) ([]*ORF, error) {
+	code := StandardGeneticCode
+	if opts.Code != 0 {
+		var err error
+		if code, err = GeneticCodeByID(opts.Code); err != nil {
+			return nil, err
+		}
+	}
+	seq := normalizeCodon(s.sequence)
+	n := len(seq)
+	var orfs []*ORF
+	for _, strand := range []byte{'+', '-'} {
+		strandSeq := seq
+		if strand == '-' {
+			strandSeq = ReverseComplement(seq)
+		}
+		for frame := 0; frame < 3; frame++ {
+			start := -1
+			i := frame
+			for ; i+3 <= n; i += 3 {
+				codon := strandSeq[i : i+3]
+				if start < 0 && (codon == "ATG" || (opts.AlternativeStarts && code.IsStart(codon))) {
+					start = i
+				}
+				if start >= 0 && code.ResolveCodon(codon) == "*" {
+					orfs = appendORF(orfs, code, strandSeq, start, i+3, strand, frame, false, opts.MinLength)
+					start = -1
+				}
+			}
+			if start >= 0 && opts.IncludePartial {
+				orfs = appendORF(orfs, code, strandSeq, start, i, strand, frame, true, opts.MinLength)
+			}
+		}
+	}
+	for _, o := range orfs {
+		if o.Strand == '-' {
+			o.Start, o.End = n-o.End, n-o.Start
+		}
+	}
+	sort.SliceStable(orfs, func(i, j int) bool {
+		if orfs[i].Start != orfs[j].Start {
+			return orfs[i].Start < orfs[j].Start
+		}
+		return orfs[i].Strand < orfs[j].Strand
+	})
+	for i, o := range orfs {
+		o.Sequence.SetID(fmt.Sprintf("%s_%d", s.name, i+1))
+		if o.Strand == '-' {
+			o.Sequence.SetDescription(fmt.Sprintf("[%d - %d] (REVERSE SENSE)", o.End, o.Start+1))
+		} else {
+			o.Sequence.SetDescription(fmt.Sprintf("[%d - %d]", o.Start+1, o.End))
+		}
+	}
+	return orfs, nil
+}
+
+// appendORF appends the ORF from start to end of the strand sequence if it
+// is at least minLength nucleotides long. Coordinates are converted to the
+// forward strand later.
+func appendORF(orfs []*ORF, code *GeneticCodeTable, strandSeq string, start, end int, strand byte, frame int, partial bool, minLength int) []*ORF {
+	if end-start < minLength {
+		return orfs
+	}
+	seq := &CodonSequence{code: code}
+	seq.SetSequence(strandSeq[start:end])
+	var prot strings.Builder
+	for _, codon := range seq.codons {
+		prot.WriteString(code.ResolveCodon(codon))
+	}
+	seq.prot = prot.String()
+	return append(orfs, &ORF{
+		Start:    start,
+		End:      end,
+		Strand:   strand,
+		Frame:    frame,
+		Partial:  partial,
+		Sequence: seq,
+	})
+}
+
+// SixFrames translates the nucleotide sequence in all six reading frames
+// using the NCBI translation table with the given ID, or the standard
+// genetic code if code is 0. The forward frames are named after the
+// sequence ID with the suffixes "_F1" to "_F3" and the reverse frames with
+// "_R1" to "_R3". Incomplete codons at the end of a frame are dropped.
+func (s *CharSequence) SixFrames(code int) (Alignment, error) {
+	var frames Alignment
+	for _, strand := range []string{"F", "R"} {
+		seq := s.sequence
+		if strand == "R" {
+			seq = ReverseComplement(seq)
+		}
+		for frame := 0; frame < 3; frame++ {
+			prot, err := TranslateWithOptions(seq, TranslateOptions{Code: code, Frame: frame, TrimPartial: true})
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, NewCharSequence(fmt.Sprintf("%s_%s%d", s.name, strand, frame+1), "", prot))
+		}
+	}
+	return frames, nil
+}
@@ -0,0 +1,96 @@
+package gofasta
+
+import "testing"
+
+func TestCharSequence_FindORFs(t *testing.T) {
+	// forward ORF ATG AAA TGA at 2-11, reverse ORF of CATTTTCAT at 13-22
+	s := NewCharSequence("seq1", "", "CCATGAAATGACCTTATTTCATGG")
+	orfs, err := s.FindORFs(ORFOptions{})
+	if err != nil {
+		t.Fatalf("FindORFs: unexpected error %v", err)
+	}
+	exp := []struct {
+		start, end int
+		strand     byte
+		frame      int
+		id, desc   string
+		seq, prot  string
+	}{
+		{2, 11, '+', 2, "seq1_1", "[3 - 11]", "ATGAAATGA", "MK*"},
+		{13, 22, '-', 2, "seq1_2", "[22 - 14] (REVERSE SENSE)", "ATGAAATAA", "MK*"},
+	}
+	if len(orfs) != len(exp) {
+		t.Fatalf("FindORFs: expected %d ORFs, actual %d", len(exp), len(orfs))
+	}
+	for i, e := range exp {
+		o := orfs[i]
+		if e.start != o.Start || e.end != o.End || e.strand != o.Strand || e.frame != o.Frame {
+			t.Errorf("FindORFs: expected %d-%d %c %d, actual %d-%d %c %d", e.start, e.end, e.strand, e.frame, o.Start, o.End, o.Strand, o.Frame)
+		}
+		if e.id != o.Sequence.ID() || e.desc != o.Sequence.Description() {
+			t.Errorf("FindORFs: expected %#v %#v, actual %#v %#v", e.id, e.desc, o.Sequence.ID(), o.Sequence.Description())
+		}
+		if e.seq != o.Sequence.Sequence() || e.prot != o.Sequence.Prot() {
+			t.Errorf("FindORFs: expected %#v %#v, actual %#v %#v", e.seq, e.prot, o.Sequence.Sequence(), o.Sequence.Prot())
+		}
+		if o.Length() != 9 || o.Partial {
+			t.Errorf("FindORFs: unexpected length %d or partial ORF", o.Length())
+		}
+	}
+	if orfs, _ := s.FindORFs(ORFOptions{MinLength: 10}); len(orfs) != 0 {
+		t.Errorf("FindORFs: expected no ORFs longer than 10, actual %d", len(orfs))
+	}
+}
+
+func TestCharSequence_FindORFs_Options(t *testing.T) {
+	s := NewCharSequence("seq1", "", "TTGCCCTAAATGCCC")
+	orfs, _ := s.FindORFs(ORFOptions{})
+	if len(orfs) != 0 {
+		t.Errorf("FindORFs: expected no ORFs, actual %d", len(orfs))
+	}
+	orfs, _ = s.FindORFs(ORFOptions{AlternativeStarts: true, IncludePartial: true})
+	if len(orfs) != 2 {
+		t.Fatalf("FindORFs: expected %d ORFs, actual %d", 2, len(orfs))
+	}
+	if orfs[0].Sequence.Sequence() != "TTGCCCTAA" || !orfs[1].Partial || orfs[1].Sequence.Sequence() != "ATGCCC" {
+		t.Errorf("FindORFs: unexpected ORFs %v %v", orfs[0].Sequence, orfs[1].Sequence)
+	}
+	// TGA is not a stop codon in the vertebrate mitochondrial code
+	orfs, _ = NewCharSequence("m", "", "ATGTGATAG").FindORFs(ORFOptions{Code: 2})
+	if len(orfs) != 1 || orfs[0].Sequence.Prot() != "MW*" {
+		t.Errorf("FindORFs: unexpected ORFs %v", orfs)
+	}
+	if _, err := s.FindORFs(ORFOptions{Code: 7}); err == nil {
+		t.Errorf("FindORFs: expected error for unknown table")
+	}
+}
+
+func TestCharSequence_FindORFs_Ambiguous(t *testing.T) {
+	s := NewCharSequence("s", "", "ATGGCNCTRTAA")
+	orfs, err := s.FindORFs(ORFOptions{})
+	if err != nil {
+		t.Fatalf("FindORFs: unexpected error %v", err)
+	}
+	if len(orfs) != 1 || orfs[0].Strand != '+' {
+		t.Fatalf("FindORFs: expected 1 forward ORF, actual %#v", orfs)
+	}
+	if exp := "MAL*"; exp != orfs[0].Sequence.Prot() {
+		t.Errorf("FindORFs: expected %#v, actual %#v", exp, orfs[0].Sequence.Prot())
+	}
+}
+
+func TestCharSequence_SixFrames(t *testing.T) {
+	frames, err := NewCharSequence("s", "", "ATGAAATGA").SixFrames(0)
+	if err != nil {
+		t.Fatalf("SixFrames: unexpected error %v", err)
+	}
+	exp := []struct{ id, prot string }{
+		{"s_F1", "MK*"}, {"s_F2", "*N"}, {"s_F3", "EM"},
+		{"s_R1", "SFH"}, {"s_R2", "HF"}, {"s_R3", "IS"},
+	}
+	for i, e := range exp {
+		if e.id != frames[i].ID() || e.prot != frames[i].Sequence() {
+			t.Errorf("SixFrames: expected %#v %#v, actual %#v %#v", e.id, e.prot, frames[i].ID(), frames[i].Sequence())
+		}
+	}
+}
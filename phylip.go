@@ -0,0 +1,249 @@
+package gofasta
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// phylipNameWidth is the width of the name field in strict PHYLIP files.
+const phylipNameWidth = 10
+
+// phylipBlockWidth is the number of characters per sequence in each block
+// of interleaved PHYLIP output.
+const phylipBlockWidth = 60
+
+// ErrInvalidAlignment is returned when writing an alignment whose sequences
+// do not all have the same length.
+var ErrInvalidAlignment = errors.New("sequences in alignment do not have the same length")
+
+// PhylipFormat describes a variant of the PHYLIP format.
+// In strict PHYLIP, names occupy exactly the first 10 characters of a line.
+// In relaxed PHYLIP, as used by RAxML, PhyML and IQ-TREE, names can have
+// any length and are separated from the sequence by whitespace.
+// Interleaved files split sequences into blocks, with names only in the
+// first block. Sequential files list each sequence in full.
+type PhylipFormat struct {
+	Relaxed     bool
+	Interleaved bool
+}
+
+// ReadPhylipFile reads a PHYLIP file into an Alignment.
+// Compressed files are decompressed transparently.
+func ReadPhylipFile(path string, format PhylipFormat, toCodon bool) (Alignment, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadPhylip(file, format, toCodon)
+}
+
+// ReadPhylip reads a PHYLIP-formatted io.Reader stream into an Alignment.
+// If toCodon is true, sequences are stored as CodonSequence, otherwise as
+// CharSequence.
+func ReadPhylip(r io.Reader, format PhylipFormat, toCodon bool) (Alignment, error) {
+	p := &phylipParser{scanner: bufio.NewScanner(r)}
+	p.scanner.Buffer(nil, 1<<30)
+
+	header, err := p.nextLine()
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return nil, &ParseError{Line: p.line, Reason: "expected number of sequences and characters"}
+	}
+	ntax, err1 := strconv.Atoi(fields[0])
+	nchar, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || ntax < 0 || nchar < 0 {
+		return nil, &ParseError{Line: p.line, Reason: fmt.Sprintf("invalid dimensions %q", header)}
+	}
+
+	names := make([]string, ntax)
+	seqs := make([]phylipSequence, ntax)
+	for i := 0; i < ntax; i++ {
+		line, err := p.nextLine()
+		if err != nil {
+			return nil, err
+		}
+		names[i], line = splitPhylipName(line, format.Relaxed)
+		if len(names[i]) == 0 {
+			return nil, &ParseError{Line: p.line, Reason: "missing sequence name"}
+		}
+		if err = p.appendSequence(&seqs[i], line, nchar, names[i]); err != nil {
+			return nil, err
+		}
+		for !format.Interleaved && seqs[i].n < nchar {
+			if line, err = p.nextLine(); err != nil {
+				return nil, err
+			}
+			if err = p.appendSequence(&seqs[i], line, nchar, names[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i := 0; format.Interleaved && !phylipComplete(seqs, nchar); i = (i + 1) % ntax {
+		line, err := p.nextLine()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.appendSequence(&seqs[i], line, nchar, names[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	a := make(Alignment, ntax)
+	for i := range a {
+		if a[i], err = newSequence(names[i], "", seqs[i].b.String(), toCodon); err != nil {
+			return nil, &ParseError{ID: names[i], Reason: "invalid codon sequence", Err: err}
+		}
+	}
+	return a, nil
+}
+
+type phylipSequence struct {
+	b strings.Builder
+	n int
+}
+
+type phylipParser struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// nextLine returns the next non-blank line.
+func (p *phylipParser) nextLine() (string, error) {
+	for p.scanner.Scan() {
+		p.line++
+		line := strings.TrimRight(p.scanner.Text(), "\r")
+		if len(strings.TrimSpace(line)) > 0 {
+			return line, nil
+		}
+	}
+	if err := p.scanner.Err(); err != nil {
+		return "", &ParseError{Line: p.line, Reason: "cannot read input", Err: err}
+	}
+	return "", &ParseError{Line: p.line, Reason: "unexpected end of file", Err: io.ErrUnexpectedEOF}
+}
+
+// appendSequence appends the characters of line, ignoring whitespace, to
+// the sequence and checks that it does not exceed nchar characters.
+func (p *phylipParser) appendSequence(seq *phylipSequence, line string, nchar int, name string) error {
+	for _, c := range line {
+		if !unicode.IsSpace(c) {
+			seq.b.WriteRune(c)
+			seq.n++
+		}
+	}
+	if seq.n > nchar {
+		return &ParseError{Line: p.line, ID: name, Reason: fmt.Sprintf("sequence has %d characters, expected %d", seq.n, nchar)}
+	}
+	return nil
+}
+
+func phylipComplete(seqs []phylipSequence, nchar int) bool {
+	for i := range seqs {
+		if seqs[i].n < nchar {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPhylipName separates the sequence name from the rest of the line.
+func splitPhylipName(line string, relaxed bool) (name, rest string) {
+	if relaxed {
+		line = strings.TrimLeftFunc(line, unicode.IsSpace)
+		i := strings.IndexFunc(line, unicode.IsSpace)
+		if i < 0 {
+			return line, ""
+		}
+		return line[:i], line[i:]
+	}
+	runes := []rune(line)
+	if len(runes) <= phylipNameWidth {
+		return strings.TrimSpace(line), ""
+	}
+	return strings.TrimSpace(string(runes[:phylipNameWidth])), string(runes[phylipNameWidth:])
+}
+
+// WritePhylipFile saves the sequence alignment to a PHYLIP file.
+func (a Alignment) WritePhylipFile(path string, format PhylipFormat) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = a.WritePhylip(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WritePhylip writes the sequence alignment in the PHYLIP format to w.
+// The alignment must be valid, see Alignment.Valid. In strict format, names
+// longer than 10 characters are rejected. In relaxed format, names
+// containing whitespace are rejected. Interleaved output is written in
+// blocks of 60 characters.
+func (a Alignment) WritePhylip(w io.Writer, format PhylipFormat) error {
+	if !a.Valid() {
+		return ErrInvalidAlignment
+	}
+	nameWidth := phylipNameWidth
+	if format.Relaxed {
+		nameWidth = 0
+	}
+	for _, s := range a {
+		n := utf8.RuneCountInString(s.ID())
+		if len(s.ID()) == 0 || strings.IndexFunc(s.ID(), unicode.IsSpace) >= 0 {
+			return fmt.Errorf("invalid PHYLIP name %q", s.ID())
+		}
+		if !format.Relaxed && n > phylipNameWidth {
+			return fmt.Errorf("name %q is longer than %d characters", s.ID(), phylipNameWidth)
+		}
+		if format.Relaxed && n+1 > nameWidth {
+			nameWidth = n + 1
+		}
+	}
+	nchar := 0
+	if len(a) > 0 {
+		nchar = utf8.RuneCountInString(a[0].Sequence())
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%d %d\n", len(a), nchar)
+	if !format.Interleaved {
+		for _, s := range a {
+			fmt.Fprintf(bw, "%-*s%s\n", nameWidth, s.ID(), s.Sequence())
+		}
+		return bw.Flush()
+	}
+	seqs := make([][]rune, len(a))
+	for i, s := range a {
+		seqs[i] = []rune(s.Sequence())
+	}
+	for start := 0; start < nchar || start == 0; start += phylipBlockWidth {
+		end := start + phylipBlockWidth
+		if end > nchar {
+			end = nchar
+		}
+		if start > 0 {
+			bw.WriteByte('\n')
+		}
+		for i, s := range a {
+			if start == 0 {
+				fmt.Fprintf(bw, "%-*s", nameWidth, s.ID())
+			}
+			bw.WriteString(string(seqs[i][start:end]))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
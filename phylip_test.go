@@ -0,0 +1,105 @@
+package gofasta
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func phylipTestAlignment() Alignment {
+	// 84 characters to produce two interleaved blocks
+	return Alignment{
+		NewCharSequence("alpha", "", strings.Repeat("AAGCTNGGGCATTTCAGGGTGAGCCCGGGCAATACAGGGTAT", 2)),
+		NewCharSequence("beta_longname", "", strings.Repeat("AAGCTNGGGCATTTCAGGGTGAGCCCGGGCAATACAGGGTAT", 2)),
+		NewCharSequence("gamma", "", strings.Repeat("AAGCCTTGGCAGTGCAGGGTGAGCCGTGGCCGGGCACGGTAT", 2)),
+	}
+}
+
+func TestReadPhylip_StrictSequential(t *testing.T) {
+	r := strings.NewReader("3 12\n" +
+		"Turkey    AAGCTNGGGC\n" +
+		"AT\n" +
+		"Salmo gairAAGCCTTGGCAG\n" +
+		"H. SapiensACCGGTTGGCCG\n")
+	a, err := ReadPhylip(r, PhylipFormat{}, false)
+	if err != nil {
+		t.Fatalf("ReadPhylip: unexpected error %v", err)
+	}
+	expNames := []string{"Turkey", "Salmo gair", "H. Sapiens"}
+	expSeqs := []string{"AAGCTNGGGCAT", "AAGCCTTGGCAG", "ACCGGTTGGCCG"}
+	for i, s := range a {
+		if s.ID() != expNames[i] || s.Sequence() != expSeqs[i] {
+			t.Errorf("ReadPhylip: expected %s %s, actual %s %s", expNames[i], expSeqs[i], s.ID(), s.Sequence())
+		}
+	}
+}
+
+func TestReadPhylip_RelaxedInterleaved(t *testing.T) {
+	r := strings.NewReader(" 2 9 I\n" +
+		"seq_one  ATG GCG\n" +
+		"seq_two  ATG GCA\n" +
+		"\n" +
+		"TGG\n" +
+		"TGA\n")
+	a, err := ReadPhylip(r, PhylipFormat{Relaxed: true, Interleaved: true}, true)
+	if err != nil {
+		t.Fatalf("ReadPhylip: unexpected error %v", err)
+	}
+	if len(a) != 2 || a[0].ID() != "seq_one" || a[1].Sequence() != "ATGGCATGA" {
+		t.Fatalf("ReadPhylip: unexpected alignment %v", a)
+	}
+	if prot := a[1].(*CodonSequence).Prot(); prot != "MA*" {
+		t.Errorf("ReadPhylip: expected %#v, actual %#v", "MA*", prot)
+	}
+}
+
+func TestReadPhylip_Errors(t *testing.T) {
+	for _, data := range []string{
+		"2 4\na ACGT\n",
+		"1 4\na ACGTA\n",
+		"x 4\na ACGT\n",
+	} {
+		if _, err := ReadPhylip(strings.NewReader(data), PhylipFormat{Relaxed: true}, false); err == nil {
+			t.Errorf("ReadPhylip: expected error for %#v", data)
+		}
+	}
+}
+
+func TestAlignment_WritePhylip_RoundTrip(t *testing.T) {
+	a := phylipTestAlignment()
+	for _, format := range []PhylipFormat{{true, false}, {true, true}} {
+		var buff bytes.Buffer
+		if err := a.WritePhylip(&buff, format); err != nil {
+			t.Fatalf("WritePhylip(%+v): unexpected error %v", format, err)
+		}
+		actual, err := ReadPhylip(&buff, format, false)
+		if err != nil {
+			t.Fatalf("ReadPhylip(%+v): unexpected error %v", format, err)
+		}
+		if a.ToFasta() != actual.ToFasta() {
+			t.Errorf("WritePhylip(%+v): round trip mismatch\n%s", format, actual.ToFasta())
+		}
+	}
+}
+
+func TestAlignment_WritePhylip_Strict(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("alpha", "", "ACGT"),
+		NewCharSequence("beta", "", "AC-T"),
+	}
+	var buff bytes.Buffer
+	if err := a.WritePhylip(&buff, PhylipFormat{}); err != nil {
+		t.Fatalf("WritePhylip: unexpected error %v", err)
+	}
+	exp := "2 4\nalpha     ACGT\nbeta      AC-T\n"
+	if exp != buff.String() {
+		t.Errorf("WritePhylip: expected %#v, actual %#v", exp, buff.String())
+	}
+	if err := phylipTestAlignment().WritePhylip(&buff, PhylipFormat{}); err == nil {
+		t.Errorf("WritePhylip: expected error for long name in strict format")
+	}
+	a = append(a, NewCharSequence("gamma", "", "ACG"))
+	if err := a.WritePhylip(&buff, PhylipFormat{Relaxed: true}); err != ErrInvalidAlignment {
+		t.Errorf("WritePhylip: expected ErrInvalidAlignment, actual %v", err)
+	}
+}
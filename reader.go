@@ -3,86 +3,318 @@ package gofasta
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"log"
-	"os"
 	"strings"
+	"unicode"
 )
 
 // FastaFileToCharAlignment reads a FASTA file into a character-based Alignment struct.
+// Compressed files are decompressed transparently.
 func FastaFileToCharAlignment(path string) (sequences Alignment) {
-	file, err := os.Open(path)
+	sequences, err := ReadFastaFile(path, false)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer file.Close()
-	return FastaToAlignment(file, false)
+	return
 }
 
 // FastaFileToCodonAlignment reads a FASTA file into a codon-based Alignment struct.
+// Compressed files are decompressed transparently.
 func FastaFileToCodonAlignment(path string) (sequences Alignment) {
-	file, err := os.Open(path)
+	sequences, err := ReadFastaFile(path, true)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer file.Close()
-	return FastaToAlignment(file, true)
+	return
 }
 
 // FastaToAlignment reads a FASTA-formatted io.Reader stream into an Alignment struct.
 func FastaToAlignment(file io.Reader, toCodon bool) (sequences Alignment) {
-	reader := bufio.NewReader(file)
+	sequences, err := ReadFasta(file, toCodon)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// ReadFastaFile reads a FASTA file into an Alignment. If toCodon is true,
+// sequences are stored as CodonSequence, otherwise as CharSequence.
+// Compressed files are decompressed transparently (see OpenFile).
+// Unlike FastaFileToCharAlignment and FastaFileToCodonAlignment, errors are
+// returned to the caller instead of terminating the program.
+func ReadFastaFile(path string, toCodon bool) (Alignment, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFasta(file, toCodon)
+}
+
+// ReadFasta reads a FASTA-formatted io.Reader stream into an Alignment.
+// Unlike FastaToAlignment, parsing problems are returned as a *ParseError
+// instead of causing a panic.
+func ReadFasta(file io.Reader, toCodon bool) (sequences Alignment, err error) {
+	reader := NewFastaReader(file, toCodon)
+	for reader.Next() {
+		sequences = append(sequences, reader.Record())
+	}
+	if err = reader.Err(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// FastaReader reads FASTA-formatted records one at a time from an io.Reader.
+// Only the record currently being assembled is held in memory, which makes
+// it suitable for files that are too large to load as a whole Alignment.
+//
+// Lines starting with '#' or ';' are treated as comments and skipped.
+// A header line starts with '>' and is split into an ID and a description
+// at the first space. Records without any sequence data are dropped.
+//
+// Lines are split on '\n' only. A '\r' from a CRLF line ending is not
+// removed and is kept in the ID, description or sequence data of the
+// record. In strict mode, it is kept as well and each CRLF line ending is
+// also reported as an issue.
+type FastaReader struct {
+	reader  *bufio.Reader
+	toCodon bool
+
+	line       int
+	headerLine int
+	name, desc string
+	seqBuffer  bytes.Buffer
 
-	var err error
-	var name, desc string
-	var seqBuffer bytes.Buffer
-	var splitted []string
+	record Sequence
+	err    error
+	done   bool
+
+	strict     bool
+	validChars string
+	seenHeader bool
+	pending    bool
+	seenIDs    map[string]int
+	issues     []ValidationIssue
+}
 
-	var line string
+// NewFastaReader creates a new FastaReader that reads from r. If toCodon is
+// true, records are returned as CodonSequence, otherwise as CharSequence.
+func NewFastaReader(r io.Reader, toCodon bool) *FastaReader {
+	return &FastaReader{
+		reader:  bufio.NewReader(r),
+		toCodon: toCodon,
+	}
+}
+
+// SetStrict enables strict parsing. In strict mode, the reader keeps parsing
+// after encountering a problem and collects every issue together with its
+// line and column position. Sequence data before the first header, records
+// without sequence data, duplicate IDs, headers without an ID, CRLF line
+// endings and invalid codon sequences are reported. If validChars is not
+// empty, sequence characters that are not in validChars are also reported.
+// Characters are compared case-insensitively.
+//
+// Records that can be parsed are still returned by Next. Once the end of the
+// input is reached, Err returns a *ValidationError listing all issues if any
+// were found.
+func (r *FastaReader) SetStrict(validChars string) {
+	r.strict = true
+	r.validChars = strings.ToUpper(validChars)
+	r.seenIDs = make(map[string]int)
+}
+
+// SetAlphabet enables strict parsing (see SetStrict) using the symbols of
+// the alphabet as valid characters, so that every character outside of the
+// alphabet is reported.
+func (r *FastaReader) SetAlphabet(a *Alphabet) {
+	r.SetStrict(a.ValidChars())
+}
+
+// Issues returns the problems found so far in strict mode.
+func (r *FastaReader) Issues() []ValidationIssue {
+	return r.issues
+}
+
+// Next advances the reader to the next record, which will then be available
+// through Record. It returns false when there are no more records, either
+// by reaching the end of the input or because of an error. After Next
+// returns false, Err returns the error that occurred, if any.
+func (r *FastaReader) Next() bool {
+	r.record = nil
+	if r.done {
+		return false
+	}
 	for {
-		line, err = reader.ReadString('\n')
+		line, err := r.reader.ReadString('\n')
+		if len(line) > 0 {
+			r.line++
+		}
 		line = strings.TrimSuffix(line, "\n")
+		if r.strict {
+			r.checkLine(line)
+		}
+		emitted := false
 		if strings.HasPrefix(line, ">") {
-			if seqBuffer.Len() > 0 {
-				var sequence Sequence
-				if toCodon == true {
-					sequence = NewCodonSequence(name, desc, seqBuffer.String())
-				} else {
-					sequence = NewCharSequence(name, desc, seqBuffer.String())
+			if r.seqBuffer.Len() > 0 {
+				emitted = r.emit()
+				if r.err != nil {
+					return false
 				}
-				sequences = append(sequences, sequence)
-				seqBuffer.Reset()
-				name, desc = "", ""
 			}
-			splitted = strings.SplitN(line[1:], " ", 2)
-			name = splitted[0]
-			if len(splitted) == 2 {
-				desc = splitted[1]
-			}
-		} else if strings.HasPrefix(line, "\n") {
-			continue
+			r.name, r.desc = parseFastaHeader(line)
+			r.headerLine = r.line
+			r.seenHeader = true
+			r.pending = len(r.name) > 0
 		} else if strings.HasPrefix(line, "#") {
-			continue
+			// comment line
 		} else if strings.HasPrefix(line, ";") {
-			continue
-		} else if len(name) > 0 {
-			seqBuffer.WriteString(line)
+			// comment line
+		} else if len(r.name) > 0 {
+			r.seqBuffer.WriteString(line)
 		}
 		if err == io.EOF {
-			break
+			r.done = true
+			if r.strict && r.pending && r.seqBuffer.Len() == 0 {
+				r.addIssue(r.headerLine, 1, IssueEmptyRecord, "record has no sequence data")
+			}
+			if !emitted && r.seqBuffer.Len() > 0 {
+				emitted = r.emit()
+				if r.err != nil {
+					return false
+				}
+			}
+			if len(r.issues) > 0 {
+				r.err = &ValidationError{Issues: r.issues}
+			}
+			return emitted
 		} else if err != nil {
-			panic("[Error!] alignment file may be malformed")
+			r.fail(&ParseError{Line: r.line, ID: r.name, Reason: "cannot read input", Err: err})
+			return false
 		}
+		if emitted {
+			return true
+		}
+	}
+}
+
+// checkLine reports strict mode issues found in a single line. The line is
+// checked before it is used to update the state of the reader.
+func (r *FastaReader) checkLine(line string) {
+	if strings.HasSuffix(line, "\r") {
+		r.addIssue(r.line, len([]rune(line)), IssueCarriageReturn, "line ends with CRLF")
+		line = strings.TrimSuffix(line, "\r")
 	}
-	if seqBuffer.Len() > 0 {
-		var sequence Sequence
-		if toCodon == true {
-			sequence = NewCodonSequence(name, desc, seqBuffer.String())
-		} else {
-			sequence = NewCharSequence(name, desc, seqBuffer.String())
+	switch {
+	case strings.HasPrefix(line, ">"):
+		if r.pending && r.seqBuffer.Len() == 0 {
+			r.addIssue(r.headerLine, 1, IssueEmptyRecord, "record has no sequence data")
 		}
-		sequences = append(sequences, sequence)
-		seqBuffer.Reset()
+		name, _ := parseFastaHeader(line)
+		if len(name) == 0 {
+			r.addIssueWithID(r.line, 2, "", IssueMissingID, "header has no ID")
+			return
+		}
+		if first, ok := r.seenIDs[name]; ok {
+			r.addIssueWithID(r.line, 2, name, IssueDuplicateID, fmt.Sprintf("duplicate ID, first seen on line %d", first))
+			return
+		}
+		r.seenIDs[name] = r.line
+	case strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+		return
+	case len(line) == 0:
+		return
+	case !r.seenHeader:
+		r.addIssue(r.line, 1, IssueOrphanSequence, "sequence data before the first header")
+	default:
+		if len(r.validChars) == 0 {
+			return
+		}
+		for col, c := range []rune(line) {
+			if !strings.ContainsRune(r.validChars, unicode.ToUpper(c)) {
+				r.addIssue(r.line, col+1, IssueIllegalChar, fmt.Sprintf("illegal character %q", c))
+			}
+		}
+	}
+}
+
+// addIssue records a strict mode issue for the record currently being parsed.
+func (r *FastaReader) addIssue(line, col int, kind IssueKind, msg string) {
+	r.addIssueWithID(line, col, r.name, kind, msg)
+}
+
+// addIssueWithID records a strict mode issue for the given record ID.
+func (r *FastaReader) addIssueWithID(line, col int, id string, kind IssueKind, msg string) {
+	r.issues = append(r.issues, ValidationIssue{
+		Line:    line,
+		Column:  col,
+		ID:      id,
+		Kind:    kind,
+		Message: msg,
+	})
+}
+
+// Record returns the most recent record read by a call to Next.
+func (r *FastaReader) Record() Sequence {
+	return r.record
+}
+
+// Err returns the first non-EOF error that was encountered by the reader.
+// Parsing problems are reported as a *ParseError. In strict mode (see
+// SetStrict), the issues found in the input are instead reported together
+// as a *ValidationError once the end of the input is reached.
+func (r *FastaReader) Err() error {
+	return r.err
+}
+
+// emit creates a Sequence from the buffered record, stores it as the current
+// record and resets the buffer for the next record. It returns false if the
+// record could not be created. In strict mode, the problem is recorded as an
+// issue and the record is skipped, otherwise the reader stops with an error.
+func (r *FastaReader) emit() bool {
+	sequence, err := newSequence(r.name, r.desc, r.seqBuffer.String(), r.toCodon)
+	if err != nil && r.strict {
+		r.addIssue(r.headerLine, 1, IssueCodonLength, err.Error())
+	} else if err != nil {
+		r.fail(&ParseError{Line: r.headerLine, ID: r.name, Reason: "invalid codon sequence", Err: err})
+		return false
+	}
+	r.seqBuffer.Reset()
+	r.name, r.desc = "", ""
+	r.pending = false
+	r.record = sequence
+	return err == nil
+}
+
+// fail stops the reader and records the error.
+func (r *FastaReader) fail(err error) {
+	r.done = true
+	r.record = nil
+	r.err = err
+}
+
+// parseFastaHeader splits a FASTA header line into its ID and description.
+// The leading '>' is removed and the line is split at the first space.
+func parseFastaHeader(line string) (name, desc string) {
+	splitted := strings.SplitN(line[1:], " ", 2)
+	name = splitted[0]
+	if len(splitted) == 2 {
+		desc = splitted[1]
 	}
 	return
 }
+
+// newSequence creates a CodonSequence if toCodon is true, otherwise a
+// CharSequence.
+func newSequence(name, desc, seq string, toCodon bool) (Sequence, error) {
+	if toCodon {
+		s, err := NewCodonSequenceChecked(name, desc, seq)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return NewCharSequence(name, desc, seq), nil
+}
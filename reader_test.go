@@ -1,6 +1,9 @@
 package gofasta
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -60,3 +63,88 @@ func TestFastaToAlignment_Codon(t *testing.T) {
 		}
 	}
 }
+
+func TestFastaReader(t *testing.T) {
+	r := strings.NewReader("# comment1\n\n" +
+		"; comment2\n" +
+		">a test\n" +
+		"TTT---TTC\n" +
+		"TTATTG\n" +
+		">empty\n" +
+		">b\n" +
+		"TAT---TTCTTTTTG\n" +
+		">c test1 test2\n" +
+		"TTTTTCTTC---TTG\n")
+	exp := []Sequence{
+		NewCharSequence("a", "test", "TTT---TTCTTATTG"),
+		NewCharSequence("b", "", "TAT---TTCTTTTTG"),
+		NewCharSequence("c", "test1 test2", "TTTTTCTTC---TTG"),
+	}
+	reader := NewFastaReader(r, false)
+	i := 0
+	for reader.Next() {
+		s := reader.Record()
+		if i >= len(exp) {
+			t.Fatalf("FastaReader: expected %d records, got more", len(exp))
+		}
+		if s.ID() != exp[i].ID() {
+			t.Errorf("FastaReader: name mismatch in sequence %v: %s != %s", i, exp[i].ID(), s.ID())
+		}
+		if s.Description() != exp[i].Description() {
+			t.Errorf("FastaReader: description mismatch in sequence %v: %s != %s", i, exp[i].Description(), s.Description())
+		}
+		if s.Sequence() != exp[i].Sequence() {
+			t.Errorf("FastaReader: sequence mismatch in sequence %v: %s != %s", i, exp[i].Sequence(), s.Sequence())
+		}
+		i++
+	}
+	if i != len(exp) {
+		t.Errorf("FastaReader: expected %d records, actual %d", len(exp), i)
+	}
+	if err := reader.Err(); err != nil {
+		t.Errorf("FastaReader: unexpected error %v", err)
+	}
+	if reader.Next() {
+		t.Errorf("FastaReader: expected Next to return false after end of input")
+	}
+}
+
+func TestFastaReader_Codon(t *testing.T) {
+	r := strings.NewReader(">a\nATGGCG\nTGG")
+	reader := NewFastaReader(r, true)
+	if !reader.Next() {
+		t.Fatalf("FastaReader: expected a record, err %v", reader.Err())
+	}
+	s, ok := reader.Record().(*CodonSequence)
+	if !ok {
+		t.Fatalf("FastaReader: expected *CodonSequence, actual %T", reader.Record())
+	}
+	if exp := "MAW"; s.Prot() != exp {
+		t.Errorf("FastaReader: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if reader.Next() {
+		t.Errorf("FastaReader: expected a single record")
+	}
+}
+
+func TestReadFasta_CodonError(t *testing.T) {
+	r := strings.NewReader(">a\nATGGCGTGG\n>b bad\nATGGC\nGT\n>c\nTGG\n")
+	_, err := ReadFasta(r, true)
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("ReadFasta: expected *ParseError, actual %#v", err)
+	}
+	if perr.Line != 3 || perr.ID != "b" {
+		t.Errorf("ReadFasta: expected error at line 3 for record b, actual line %d record %s", perr.Line, perr.ID)
+	}
+	if !errors.Is(err, ErrCodonLength) {
+		t.Errorf("ReadFasta: expected error to wrap ErrCodonLength")
+	}
+}
+
+func TestReadFastaFile_Missing(t *testing.T) {
+	_, err := ReadFastaFile(filepath.Join(t.TempDir(), "missing.fa"), false)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadFastaFile: expected os.ErrNotExist, actual %v", err)
+	}
+}
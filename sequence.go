@@ -12,6 +12,8 @@ type Sequence interface {
 type SequenceMeta interface {
 	ID() string
 	Description() string
+	Attribute(string) (interface{}, bool)
+	Attributes() map[string]interface{}
 }
 
 // SequenceGetter contains methods to retrieve information about sequence data.
@@ -26,6 +28,8 @@ type SequenceGetter interface {
 type SequenceSetter interface {
 	SetID(string)
 	SetDescription(string)
+	SetAttribute(string, interface{})
+	DeleteAttribute(string)
 	SetSequence(string)
 	ToUpper()
 	ToLower()
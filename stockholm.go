@@ -0,0 +1,252 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StockholmAnnotation is a tag and its free text value, as used in #=GF
+// and #=GS lines.
+type StockholmAnnotation struct {
+	Tag   string
+	Value string
+}
+
+// Stockholm holds a Stockholm alignment together with its annotations.
+//
+// GF holds the per-file annotations in the order they appear. GS holds the
+// per-sequence annotations keyed by sequence ID. GR holds the per-residue
+// annotations keyed by sequence ID and tag, such as SS or PP. GC holds the
+// per-column annotations keyed by tag, such as SS_cons or RF. Per-residue
+// and per-column annotations have one character per alignment column.
+type Stockholm struct {
+	Alignment Alignment
+	GF        []StockholmAnnotation
+	GS        map[string][]StockholmAnnotation
+	GR        map[string]map[string]string
+	GC        map[string]string
+}
+
+// NewStockholm creates a Stockholm alignment without annotations.
+func NewStockholm(a Alignment) *Stockholm {
+	return &Stockholm{
+		Alignment: a,
+		GS:        make(map[string][]StockholmAnnotation),
+		GR:        make(map[string]map[string]string),
+		GC:        make(map[string]string),
+	}
+}
+
+// ReadStockholmFile reads all alignments in a Stockholm file.
+// Compressed files are decompressed transparently.
+func ReadStockholmFile(path string) ([]*Stockholm, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadStockholm(file)
+}
+
+// ReadStockholm reads all alignments in a Stockholm-formatted io.Reader
+// stream, such as a Pfam or Rfam database file. Sequences are stored as
+// CharSequence. Alignments split into several blocks are joined.
+func ReadStockholm(r io.Reader) (alignments []*Stockholm, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	var current *Stockholm
+	var seqs map[string]*strings.Builder
+	var order []string
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if len(trimmed) == 0 {
+				continue
+			}
+			if !strings.HasPrefix(trimmed, "# STOCKHOLM") {
+				return nil, &ParseError{Line: lineNum, Reason: "missing '# STOCKHOLM' header"}
+			}
+			current = NewStockholm(nil)
+			seqs = make(map[string]*strings.Builder)
+			order = nil
+			continue
+		}
+		switch {
+		case len(trimmed) == 0:
+		case trimmed == "//":
+			for _, name := range order {
+				current.Alignment = append(current.Alignment, NewCharSequence(name, "", seqs[name].String()))
+			}
+			alignments = append(alignments, current)
+			current = nil
+		case strings.HasPrefix(line, "#=GF"):
+			fields := splitFieldsN(line, 3)
+			if len(fields) < 2 {
+				return nil, &ParseError{Line: lineNum, Reason: "invalid #=GF line"}
+			}
+			current.GF = append(current.GF, StockholmAnnotation{fields[1], fieldAt(fields, 2)})
+		case strings.HasPrefix(line, "#=GS"):
+			fields := splitFieldsN(line, 4)
+			if len(fields) < 3 {
+				return nil, &ParseError{Line: lineNum, Reason: "invalid #=GS line"}
+			}
+			current.GS[fields[1]] = append(current.GS[fields[1]], StockholmAnnotation{fields[2], fieldAt(fields, 3)})
+		case strings.HasPrefix(line, "#=GR"):
+			fields := strings.Fields(line)
+			if len(fields) != 4 {
+				return nil, &ParseError{Line: lineNum, Reason: "invalid #=GR line"}
+			}
+			if current.GR[fields[1]] == nil {
+				current.GR[fields[1]] = make(map[string]string)
+			}
+			current.GR[fields[1]][fields[2]] += fields[3]
+		case strings.HasPrefix(line, "#=GC"):
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return nil, &ParseError{Line: lineNum, Reason: "invalid #=GC line"}
+			}
+			current.GC[fields[1]] += fields[2]
+		case strings.HasPrefix(line, "#"):
+			// comment line
+		default:
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return nil, &ParseError{Line: lineNum, Reason: "expected sequence name and sequence"}
+			}
+			if _, ok := seqs[fields[0]]; !ok {
+				seqs[fields[0]] = new(strings.Builder)
+				order = append(order, fields[0])
+			}
+			seqs[fields[0]].WriteString(fields[1])
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, &ParseError{Line: lineNum, Reason: "cannot read input", Err: err}
+	}
+	if current != nil {
+		return nil, &ParseError{Line: lineNum, Reason: "missing '//' at end of alignment", Err: io.ErrUnexpectedEOF}
+	}
+	return alignments, nil
+}
+
+// splitFieldsN splits s into at most n whitespace-separated fields. The last
+// field holds the rest of the line without leading whitespace.
+func splitFieldsN(s string, n int) (fields []string) {
+	for len(fields) < n-1 {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if len(s) == 0 {
+			return
+		}
+		i := strings.IndexFunc(s, unicode.IsSpace)
+		if i < 0 {
+			return append(fields, s)
+		}
+		fields = append(fields, s[:i])
+		s = s[i:]
+	}
+	if s = strings.TrimSpace(s); len(s) > 0 {
+		fields = append(fields, s)
+	}
+	return
+}
+
+func fieldAt(fields []string, i int) string {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return ""
+}
+
+// WriteStockholmFile saves one or more alignments to a Stockholm file.
+func WriteStockholmFile(path string, alignments ...*Stockholm) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	for _, s := range alignments {
+		if err = s.Write(f); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Write writes the alignment and its annotations in the Stockholm format
+// to w. Each sequence is written on a single line. The alignment must be
+// valid, see Alignment.Valid, and per-residue and per-column annotations
+// must have the same length as the alignment.
+func (s *Stockholm) Write(w io.Writer) error {
+	if !s.Alignment.Valid() {
+		return ErrInvalidAlignment
+	}
+	ncol := 0
+	if len(s.Alignment) > 0 {
+		ncol = utf8.RuneCountInString(s.Alignment[0].Sequence())
+	}
+	labelWidth := 0
+	updateWidth := func(label string) {
+		if n := utf8.RuneCountInString(label); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	for _, seq := range s.Alignment {
+		if len(seq.ID()) == 0 || strings.IndexFunc(seq.ID(), unicode.IsSpace) >= 0 {
+			return fmt.Errorf("invalid Stockholm name %q", seq.ID())
+		}
+		updateWidth(seq.ID())
+		for tag, ann := range s.GR[seq.ID()] {
+			if n := utf8.RuneCountInString(ann); n != ncol {
+				return fmt.Errorf("#=GR %s %s has %d characters, expected %d", seq.ID(), tag, n, ncol)
+			}
+			updateWidth("#=GR " + seq.ID() + " " + tag)
+		}
+	}
+	for tag, ann := range s.GC {
+		if n := utf8.RuneCountInString(ann); n != ncol {
+			return fmt.Errorf("#=GC %s has %d characters, expected %d", tag, n, ncol)
+		}
+		updateWidth("#=GC " + tag)
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# STOCKHOLM 1.0\n")
+	for _, ann := range s.GF {
+		fmt.Fprintf(bw, "#=GF %s %s\n", ann.Tag, ann.Value)
+	}
+	for _, seq := range s.Alignment {
+		for _, ann := range s.GS[seq.ID()] {
+			fmt.Fprintf(bw, "#=GS %s %s %s\n", seq.ID(), ann.Tag, ann.Value)
+		}
+	}
+	bw.WriteByte('\n')
+	for _, seq := range s.Alignment {
+		fmt.Fprintf(bw, "%-*s %s\n", labelWidth, seq.ID(), seq.Sequence())
+		for _, tag := range sortedKeys(s.GR[seq.ID()]) {
+			fmt.Fprintf(bw, "%-*s %s\n", labelWidth, "#=GR "+seq.ID()+" "+tag, s.GR[seq.ID()][tag])
+		}
+	}
+	for _, tag := range sortedKeys(s.GC) {
+		fmt.Fprintf(bw, "%-*s %s\n", labelWidth, "#=GC "+tag, s.GC[tag])
+	}
+	bw.WriteString("//\n")
+	return bw.Flush()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
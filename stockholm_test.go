@@ -0,0 +1,99 @@
+package gofasta
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const stockholmExample = `# STOCKHOLM 1.0
+#=GF ID    trna
+#=GF DE    Transfer RNA
+#=GS seq1  AC P12345
+#=GS seq1  DR PDB; 1ABC A;
+
+seq1         GCGGAU
+#=GR seq1 SS <<<...
+seq2         GCGGAC
+#=GC SS_cons <<<...
+
+seq1         UUAGC
+#=GR seq1 SS ..>>>
+seq2         UUAGG
+#=GC SS_cons ..>>>
+#=GC RF      xxxxxxxxxxx
+//
+# STOCKHOLM 1.0
+a AC-G
+//
+`
+
+func TestReadStockholm(t *testing.T) {
+	alignments, err := ReadStockholm(strings.NewReader(stockholmExample))
+	if err != nil {
+		t.Fatalf("ReadStockholm: unexpected error %v", err)
+	}
+	if len(alignments) != 2 {
+		t.Fatalf("ReadStockholm: expected 2 alignments, actual %d", len(alignments))
+	}
+	s := alignments[0]
+	if len(s.Alignment) != 2 || s.Alignment[0].Sequence() != "GCGGAUUUAGC" || s.Alignment[1].ID() != "seq2" {
+		t.Errorf("ReadStockholm: unexpected alignment\n%s", s.Alignment.ToFasta())
+	}
+	expGF := []StockholmAnnotation{{"ID", "trna"}, {"DE", "Transfer RNA"}}
+	if !reflect.DeepEqual(expGF, s.GF) {
+		t.Errorf("ReadStockholm: expected GF %v, actual %v", expGF, s.GF)
+	}
+	expGS := []StockholmAnnotation{{"AC", "P12345"}, {"DR", "PDB; 1ABC A;"}}
+	if !reflect.DeepEqual(expGS, s.GS["seq1"]) {
+		t.Errorf("ReadStockholm: expected GS %v, actual %v", expGS, s.GS["seq1"])
+	}
+	if exp := "<<<.....>>>"; s.GR["seq1"]["SS"] != exp || s.GC["SS_cons"] != exp {
+		t.Errorf("ReadStockholm: expected SS %#v, actual %#v %#v", exp, s.GR["seq1"]["SS"], s.GC["SS_cons"])
+	}
+	if alignments[1].Alignment[0].Sequence() != "AC-G" {
+		t.Errorf("ReadStockholm: unexpected second alignment %v", alignments[1].Alignment)
+	}
+}
+
+func TestReadStockholm_Errors(t *testing.T) {
+	for _, data := range []string{
+		">a\nACGT\n",
+		"# STOCKHOLM 1.0\na ACGT\n",
+		"# STOCKHOLM 1.0\na AC GT\n//\n",
+	} {
+		if _, err := ReadStockholm(strings.NewReader(data)); err == nil {
+			t.Errorf("ReadStockholm: expected error for %#v", data)
+		}
+	}
+}
+
+func TestStockholm_Write(t *testing.T) {
+	alignments, err := ReadStockholm(strings.NewReader(stockholmExample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	if err = alignments[0].Write(&buff); err != nil {
+		t.Fatalf("Write: unexpected error %v", err)
+	}
+	actual, err := ReadStockholm(&buff)
+	if err != nil {
+		t.Fatalf("ReadStockholm: unexpected error %v", err)
+	}
+	exp := alignments[0]
+	if exp.Alignment.ToFasta() != actual[0].Alignment.ToFasta() ||
+		!reflect.DeepEqual(exp.GF, actual[0].GF) ||
+		!reflect.DeepEqual(exp.GS, actual[0].GS) ||
+		!reflect.DeepEqual(exp.GR, actual[0].GR) ||
+		!reflect.DeepEqual(exp.GC, actual[0].GC) {
+		t.Errorf("Write: round trip mismatch %+v", actual[0])
+	}
+
+	s := NewStockholm(Alignment{NewCharSequence("a", "", "ACGT")})
+	s.GC["RF"] = "xx"
+	if err = s.Write(&buff); err == nil {
+		t.Errorf("Write: expected error for annotation length mismatch")
+	}
+}
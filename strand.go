@@ -0,0 +1,209 @@
+package gofasta
+
+import "strings"
+
+// complementBases maps nucleotides, including IUPAC ambiguity codes, to
+// their complement. Case is preserved. Gaps and other symbols map to
+// themselves.
+var complementBases = func() map[byte]byte {
+	pairs := []string{"AT", "CG", "UA", "RY", "KM", "SS", "WW", "BV", "DH", "NN", "--", "..", "**", "??"}
+	m := make(map[byte]byte)
+	for _, pair := range pairs {
+		for _, p := range []string{pair, strings.ToLower(pair)} {
+			m[p[0]] = p[1]
+			if _, ok := m[p[1]]; !ok {
+				m[p[1]] = p[0]
+			}
+		}
+	}
+	return m
+}()
+
+// isRNA returns true if the nucleotide sequence contains U but no T.
+func isRNA(seq string) bool {
+	return strings.ContainsAny(seq, "Uu") && !strings.ContainsAny(seq, "Tt")
+}
+
+// Complement returns the complement of a nucleotide sequence. IUPAC
+// ambiguity codes are complemented, for example R (A or G) becomes Y
+// (C or T), and case is preserved. If the sequence contains U but no T, it
+// is treated as RNA and A is complemented to U. Gaps and characters without
+// a complement are kept as is.
+func Complement(seq string) string {
+	return complementStrand(seq, false, isRNA(seq))
+}
+
+// Reverse returns the sequence in reverse order.
+func Reverse(seq string) string {
+	runes := []rune(seq)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// ReverseComplement returns the reverse complement of a nucleotide
+// sequence. See Complement for how characters are complemented.
+func ReverseComplement(seq string) string {
+	return complementStrand(seq, true, isRNA(seq))
+}
+
+// complementStrand returns the complement of seq, reversed if reverse is
+// true. If rna is true, A is complemented to U instead of T.
+func complementStrand(seq string, reverse, rna bool) string {
+	b := make([]byte, len(seq))
+	for i := 0; i < len(seq); i++ {
+		if reverse {
+			b[i] = complementBase(seq[len(seq)-1-i], rna)
+		} else {
+			b[i] = complementBase(seq[i], rna)
+		}
+	}
+	return string(b)
+}
+
+// reverseComplement returns the reverse complement of a DNA sequence
+// without checking whether it is RNA. Characters without a complement are
+// kept as is.
+func reverseComplement(seq string) string {
+	return complementStrand(seq, true, false)
+}
+
+func complementBase(c byte, rna bool) byte {
+	comp, ok := complementBases[c]
+	if !ok {
+		return c
+	}
+	if rna && comp == 'T' {
+		return 'U'
+	} else if rna && comp == 't' {
+		return 'u'
+	}
+	return comp
+}
+
+// Reverse reverses the sequence of CharSequence.
+func (s *CharSequence) Reverse() {
+	s.sequence = Reverse(s.sequence)
+}
+
+// Complement changes the sequence of CharSequence to its complement.
+func (s *CharSequence) Complement() {
+	s.complement(false, isRNA(s.sequence))
+}
+
+// ReverseComplement changes the sequence of CharSequence to its reverse
+// complement.
+func (s *CharSequence) ReverseComplement() {
+	s.complement(true, isRNA(s.sequence))
+}
+
+func (s *CharSequence) complement(reverse, rna bool) {
+	s.sequence = complementStrand(s.sequence, reverse, rna)
+}
+
+// Reverse reverses the nucleotide sequence of CodonSequence. Codons and the
+// translated protein sequence are recomputed.
+func (s *CodonSequence) Reverse() {
+	s.SetSequence(Reverse(s.sequence))
+}
+
+// Complement changes the nucleotide sequence of CodonSequence to its
+// complement. Codons and the translated protein sequence are recomputed.
+func (s *CodonSequence) Complement() {
+	s.complement(false, isRNA(s.sequence))
+}
+
+// ReverseComplement changes the nucleotide sequence of CodonSequence to its
+// reverse complement. Codons and the translated protein sequence are
+// recomputed.
+func (s *CodonSequence) ReverseComplement() {
+	s.complement(true, isRNA(s.sequence))
+}
+
+func (s *CodonSequence) complement(reverse, rna bool) {
+	s.SetSequence(complementStrand(s.sequence, reverse, rna))
+}
+
+// Reverse reverses the sequence and quality scores of QualSequence.
+func (s *QualSequence) Reverse() {
+	s.CharSequence.Reverse()
+	reverseQuality(s.quality)
+}
+
+// ReverseComplement changes the sequence of QualSequence to its reverse
+// complement and reverses the quality scores.
+func (s *QualSequence) ReverseComplement() {
+	s.complement(true, isRNA(s.sequence))
+}
+
+func (s *QualSequence) complement(reverse, rna bool) {
+	s.CharSequence.complement(reverse, rna)
+	if reverse {
+		reverseQuality(s.quality)
+	}
+}
+
+func reverseQuality(q []byte) {
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+}
+
+// strandReverser is implemented by sequences that can be reversed and
+// complemented in place, such as CharSequence, CodonSequence and
+// QualSequence. complement takes whether the sequence is RNA so that an
+// alignment can be complemented consistently.
+type strandReverser interface {
+	Reverse()
+	complement(reverse, rna bool)
+}
+
+// isRNA returns true if any sequence in the alignment contains U and none
+// contains T.
+func (a Alignment) isRNA() bool {
+	rna := false
+	for _, s := range a {
+		seq := s.Sequence()
+		if strings.ContainsAny(seq, "Tt") {
+			return false
+		}
+		rna = rna || strings.ContainsAny(seq, "Uu")
+	}
+	return rna
+}
+
+// Reverse reverses every sequence in the alignment. Sequences that cannot
+// be reversed are left unchanged.
+func (a Alignment) Reverse() {
+	for _, s := range a {
+		if r, ok := s.(strandReverser); ok {
+			r.Reverse()
+		}
+	}
+}
+
+// Complement changes every sequence in the alignment to its complement.
+// The alignment is treated as RNA if any sequence contains U and none
+// contains T. Sequences that cannot be complemented are left unchanged.
+func (a Alignment) Complement() {
+	rna := a.isRNA()
+	for _, s := range a {
+		if r, ok := s.(strandReverser); ok {
+			r.complement(false, rna)
+		}
+	}
+}
+
+// ReverseComplement changes every sequence in the alignment to its reverse
+// complement, such as to convert an alignment to the minus strand. RNA is
+// detected as in Complement. Sequences that cannot be reverse complemented
+// are left unchanged.
+func (a Alignment) ReverseComplement() {
+	rna := a.isRNA()
+	for _, s := range a {
+		if r, ok := s.(strandReverser); ok {
+			r.complement(true, rna)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gofasta
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReverseComplement(t *testing.T) {
+	cases := []struct {
+		seq, comp, rev, revComp string
+	}{
+		{"ATGC--acgt", "TACG--tgca", "tgca--CGTA", "acgt--GCAT"},
+		{"AUGCn", "UACGn", "nCGUA", "nGCAU"},
+		{"RYKMBDHVSWN.", "YRMKVHDBSWN.", ".NWSVHDBMKYR", ".NWSBDHVKMRY"},
+	}
+	for _, c := range cases {
+		if actual := Complement(c.seq); actual != c.comp {
+			t.Errorf("Complement: expected %#v, actual %#v", c.comp, actual)
+		}
+		if actual := Reverse(c.seq); actual != c.rev {
+			t.Errorf("Reverse: expected %#v, actual %#v", c.rev, actual)
+		}
+		if actual := ReverseComplement(c.seq); actual != c.revComp {
+			t.Errorf("ReverseComplement: expected %#v, actual %#v", c.revComp, actual)
+		}
+	}
+}
+
+func TestCodonSequence_ReverseComplement(t *testing.T) {
+	s := NewCodonSequence("a", "", "ATGAAACCC")
+	s.ReverseComplement()
+	if exp := "GGGTTTCAT"; exp != s.Sequence() {
+		t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, s.Sequence())
+	}
+	if exp := "GFH"; exp != s.Prot() {
+		t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, s.Prot())
+	}
+	if exp := "CAT"; exp != s.Codon(2) {
+		t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, s.Codon(2))
+	}
+}
+
+func TestAlignment_ReverseComplement(t *testing.T) {
+	q := NewQualSequence("q", "", "AACG", []byte{1, 2, 3, 4})
+	a := Alignment{NewCharSequence("a", "", "AT-G"), NewCodonSequence("b", "", "ATG"), q}
+	a.ReverseComplement()
+	for i, exp := range []string{"C-AT", "CAT", "CGTT"} {
+		if exp != a[i].Sequence() {
+			t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, a[i].Sequence())
+		}
+	}
+	if exp := []byte{4, 3, 2, 1}; !bytes.Equal(exp, q.Quality()) {
+		t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, q.Quality())
+	}
+	a.Complement()
+	a.Reverse()
+	if exp := "AT-G"; exp != a[0].Sequence() {
+		t.Errorf("Reverse: expected %#v, actual %#v", exp, a[0].Sequence())
+	}
+}
+
+func TestAlignment_ComplementRNA(t *testing.T) {
+	a := Alignment{NewCharSequence("a", "", "ACGU"), NewCharSequence("b", "", "ACGA")}
+	a.Complement()
+	for i, exp := range []string{"UGCA", "UGCU"} {
+		if exp != a[i].Sequence() {
+			t.Errorf("Complement: expected %#v, actual %#v", exp, a[i].Sequence())
+		}
+	}
+	a.ReverseComplement()
+	for i, exp := range []string{"UGCA", "AGCA"} {
+		if exp != a[i].Sequence() {
+			t.Errorf("ReverseComplement: expected %#v, actual %#v", exp, a[i].Sequence())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package gofasta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThreadMismatch describes a problem found while threading nucleotides onto
+// a protein alignment. Position is the 1-based alignment column of the
+// protein residue, or 0 if the problem concerns the whole sequence.
+type ThreadMismatch struct {
+	ID       string
+	Position int
+	Residue  string
+	Codon    string
+	Message  string
+}
+
+func (m ThreadMismatch) String() string {
+	if m.Position == 0 {
+		return fmt.Sprintf("%s: %s", m.ID, m.Message)
+	}
+	return fmt.Sprintf("%s: column %d: %s", m.ID, m.Position, m.Message)
+}
+
+// ThreadError is returned by ThreadCodonAlignment when the nucleotide
+// sequences are inconsistent with the protein alignment. Mismatches lists
+// every problem in the order of the protein alignment.
+type ThreadError struct {
+	Mismatches []ThreadMismatch
+}
+
+func (e *ThreadError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.String()
+	}
+	return fmt.Sprintf("%d mismatch(es) between protein and nucleotide sequences:\n%s", len(e.Mismatches), strings.Join(lines, "\n"))
+}
+
+// ThreadCodonAlignment threads unaligned nucleotide sequences onto an
+// aligned protein Alignment, in the manner of PAL2NAL, and returns the
+// resulting codon Alignment of CodonSequence values. Nucleotide sequences
+// are matched to protein sequences by ID and any gaps in them are removed.
+// Each protein gap ('-' or '.') becomes "---" and each residue is replaced
+// by the next codon of the nucleotide sequence. A trailing stop codon that
+// is absent from the protein sequence is dropped.
+//
+// Every codon is translated using the NCBI translation table with the given
+// ID, or the standard genetic code if code is 0, and compared to its
+// residue. Residues 'X' and codons that translate to "X", such as codons
+// with ambiguous bases, are accepted. If any translation or length
+// mismatch is found, the sequences that could be threaded are returned
+// together with a *ThreadError listing every mismatch.
+func ThreadCodonAlignment(prot, nuc Alignment, code int) (Alignment, error) {
+	table := StandardGeneticCode
+	if code != 0 {
+		var err error
+		if table, err = GeneticCodeByID(code); err != nil {
+			return nil, err
+		}
+	}
+	if !prot.Valid() {
+		return nil, fmt.Errorf("protein sequences have different lengths: %w", ErrInvalidAlignment)
+	}
+	nucByID := make(map[string]Sequence)
+	for _, s := range nuc {
+		nucByID[s.ID()] = s
+	}
+	var result Alignment
+	var mismatches []ThreadMismatch
+	for _, p := range prot {
+		n, ok := nucByID[p.ID()]
+		if !ok {
+			return nil, fmt.Errorf("no nucleotide sequence for %q", p.ID())
+		}
+		s, issues := threadSequence(p, n, table)
+		mismatches = append(mismatches, issues...)
+		if s != nil {
+			result = append(result, s)
+		}
+	}
+	if len(mismatches) > 0 {
+		return result, &ThreadError{Mismatches: mismatches}
+	}
+	return result, nil
+}
+
+// threadSequence threads a single nucleotide sequence onto an aligned
+// protein sequence. It returns nil if the lengths do not match.
+func threadSequence(p, n Sequence, table *GeneticCodeTable) (*CodonSequence, []ThreadMismatch) {
+	isGap := func(r rune) bool { return r == '-' || r == '.' }
+	aligned := p.Sequence()
+	residues := len(strings.Map(func(r rune) rune {
+		if isGap(r) {
+			return -1
+		}
+		return r
+	}, aligned))
+	nucSeq := strings.Map(func(r rune) rune {
+		if isGap(r) {
+			return -1
+		}
+		return r
+	}, n.Sequence())
+	if len(nucSeq) == 3*(residues+1) && table.ResolveCodon(nucSeq[len(nucSeq)-3:]) == "*" {
+		nucSeq = nucSeq[:len(nucSeq)-3]
+	}
+	if len(nucSeq) != 3*residues {
+		return nil, []ThreadMismatch{{
+			ID:      p.ID(),
+			Message: fmt.Sprintf("%d residues require %d nucleotides, found %d", residues, 3*residues, len(nucSeq)),
+		}}
+	}
+	var mismatches []ThreadMismatch
+	codons := make([]string, 0, len(aligned))
+	k := 0
+	for col, r := range []rune(aligned) {
+		if isGap(r) {
+			codons = append(codons, "---")
+			continue
+		}
+		codon := nucSeq[k : k+3]
+		k += 3
+		codons = append(codons, codon)
+		residue := strings.ToUpper(string(r))
+		aa := table.ResolveCodon(codon)
+		if residue != "X" && aa != "X" && aa != residue {
+			mismatches = append(mismatches, ThreadMismatch{
+				ID:       p.ID(),
+				Position: col + 1,
+				Residue:  residue,
+				Codon:    codon,
+				Message:  fmt.Sprintf("codon %s translates to %s, expected %s", codon, aa, residue),
+			})
+		}
+	}
+	s := &CodonSequence{code: table}
+	s.name = n.ID()
+	s.description = n.Description()
+	s.SetCodons(codons)
+	copyAttributes(s, n)
+	return s, mismatches
+}
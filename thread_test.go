@@ -0,0 +1,69 @@
+package gofasta
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestThreadCodonAlignment(t *testing.T) {
+	prot := Alignment{
+		NewCharSequence("a", "", "MK-W"),
+		NewCharSequence("b", "", "M-LW"),
+	}
+	nuc := Alignment{
+		NewCharSequence("b", "", "ATGctnTGGTAA"),
+		NewCharSequence("a", "", "ATG-AAA-TGG"),
+	}
+	a, err := ThreadCodonAlignment(prot, nuc, 0)
+	if err != nil {
+		t.Fatalf("ThreadCodonAlignment: unexpected error %v", err)
+	}
+	exp := []struct{ id, seq string }{
+		{"a", "ATGAAA---TGG"},
+		{"b", "ATG---ctnTGG"},
+	}
+	for i, e := range exp {
+		if e.id != a[i].ID() || e.seq != a[i].Sequence() {
+			t.Errorf("ThreadCodonAlignment: expected %#v %#v, actual %#v %#v", e.id, e.seq, a[i].ID(), a[i].Sequence())
+		}
+	}
+	if c, ok := a[1].(*CodonSequence); !ok || c.Codon(2) != "ctn" {
+		t.Errorf("ThreadCodonAlignment: expected CodonSequence, actual %#v", a[1])
+	}
+}
+
+func TestThreadCodonAlignment_Mismatch(t *testing.T) {
+	prot := Alignment{
+		NewCharSequence("a", "", "MK-W"),
+		NewCharSequence("b", "", "M-LW"),
+		NewCharSequence("c", "", "M-X-"),
+	}
+	nuc := Alignment{
+		NewCharSequence("a", "", "ATGAAATGA"),
+		NewCharSequence("b", "", "ATGCTG"),
+		NewCharSequence("c", "", "ATGNNN"),
+	}
+	a, err := ThreadCodonAlignment(prot, nuc, 0)
+	var terr *ThreadError
+	if !errors.As(err, &terr) {
+		t.Fatalf("ThreadCodonAlignment: expected ThreadError, actual %v", err)
+	}
+	exp := []ThreadMismatch{
+		{ID: "a", Position: 4, Residue: "W", Codon: "TGA", Message: "codon TGA translates to *, expected W"},
+		{ID: "b", Message: "3 residues require 9 nucleotides, found 6"},
+	}
+	if !reflect.DeepEqual(exp, terr.Mismatches) {
+		t.Errorf("ThreadCodonAlignment: expected %#v, actual %#v", exp, terr.Mismatches)
+	}
+	if len(a) != 2 || a[1].ID() != "c" {
+		t.Errorf("ThreadCodonAlignment: expected sequences a and c, actual %#v", a)
+	}
+	// TGA codes for W in the vertebrate mitochondrial code
+	if _, err := ThreadCodonAlignment(prot[:1], nuc[:1], 2); err != nil {
+		t.Errorf("ThreadCodonAlignment: unexpected error %v", err)
+	}
+	if _, err := ThreadCodonAlignment(prot, nuc[:2], 0); err == nil {
+		t.Errorf("ThreadCodonAlignment: expected error for missing nucleotide sequence")
+	}
+}
@@ -0,0 +1,123 @@
+package gofasta
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// FrameshiftChar is the amino acid symbol used for partial codons, that
+// is codons mixing gaps and nucleotides such as "A-G" and incomplete codons
+// at the end of a sequence. It distinguishes likely frameshifts from
+// unknown codons, which are translated to "X".
+const FrameshiftChar = "!"
+
+// iupacExpansions maps nucleotides and IUPAC ambiguity codes to the
+// nucleotides they represent.
+var iupacExpansions = map[byte]string{
+	'A': "A", 'C': "C", 'G': "G", 'T': "T",
+	'R': "AG", 'Y': "CT", 'S': "CG", 'W': "AT", 'K': "GT", 'M': "AC",
+	'B': "CGT", 'D': "AGT", 'H': "ACT", 'V': "ACG", 'N': "ACGT",
+}
+
+// normalizeCodon converts a codon to uppercase DNA.
+func normalizeCodon(codon string) string {
+	return strings.Replace(strings.ToUpper(codon), "U", "T", -1)
+}
+
+// ResolveCodon translates a codon like TranslateCodon but also accepts
+// lowercase and RNA codons and resolves IUPAC ambiguity codes. An ambiguous
+// codon is translated if all the codons it represents encode the same
+// amino acid, for example "CTN" to "L" and "TAR" to "*", otherwise it is
+// translated to "X". A codon mixing gaps and nucleotides is translated to
+// FrameshiftChar.
+func (t *GeneticCodeTable) ResolveCodon(codon string) string {
+	codon = normalizeCodon(codon)
+	if len(codon) != 3 {
+		return FrameshiftChar
+	}
+	gaps := strings.Count(codon, "-") + strings.Count(codon, ".")
+	if gaps == 3 {
+		return "-"
+	} else if gaps > 0 {
+		return FrameshiftChar
+	}
+	if idx := codonIndex(codon); idx >= 0 {
+		return t.AminoAcids[idx : idx+1]
+	}
+	var expansions [3]string
+	for i := 0; i < 3; i++ {
+		bases, ok := iupacExpansions[codon[i]]
+		if !ok {
+			return "X"
+		}
+		expansions[i] = bases
+	}
+	aa := byte(0)
+	for _, b1 := range []byte(expansions[0]) {
+		for _, b2 := range []byte(expansions[1]) {
+			for _, b3 := range []byte(expansions[2]) {
+				c := t.AminoAcids[codonIndex(string([]byte{b1, b2, b3}))]
+				if aa == 0 {
+					aa = c
+				} else if c != aa {
+					return "X"
+				}
+			}
+		}
+	}
+	return string(aa)
+}
+
+// TranslateOptions configures TranslateWithOptions.
+//
+// Code is the NCBI translation table ID; 0 selects the standard genetic
+// code. Frame is the number of nucleotides skipped before the first codon
+// and must be 0, 1 or 2. If StopAtStop is true, translation ends before the
+// first stop codon. If StartAsM is true, the first codon is translated to
+// "M" if it is a start codon of the table, such as "TTG" in the standard
+// code. If TrimPartial is true, an incomplete codon at the end of the
+// sequence is dropped instead of being translated to FrameshiftChar.
+type TranslateOptions struct {
+	Code        int
+	Frame       int
+	StopAtStop  bool
+	StartAsM    bool
+	TrimPartial bool
+}
+
+// TranslateWithOptions converts nucleotides into amino acids in the
+// reading frame given by opts. Unlike Translate, lowercase and RNA codons
+// are accepted, IUPAC ambiguity codes are resolved and partial codons are
+// marked (see ResolveCodon).
+func TranslateWithOptions(s string, opts TranslateOptions) (string, error) {
+	code := StandardGeneticCode
+	if opts.Code != 0 {
+		var err error
+		if code, err = GeneticCodeByID(opts.Code); err != nil {
+			return "", err
+		}
+	}
+	if opts.Frame < 0 || opts.Frame > 2 {
+		return "", fmt.Errorf("invalid reading frame %d", opts.Frame)
+	}
+	var buff bytes.Buffer
+	for i := opts.Frame; i < len(s); i += 3 {
+		if i+3 > len(s) {
+			if !opts.TrimPartial {
+				buff.WriteString(FrameshiftChar)
+			}
+			break
+		}
+		codon := s[i : i+3]
+		aa := code.ResolveCodon(codon)
+		if i == opts.Frame && opts.StartAsM && code.IsStart(normalizeCodon(codon)) {
+			aa = "M"
+		}
+		if aa == "*" && opts.StopAtStop {
+			break
+		}
+		buff.WriteString(aa)
+	}
+	return buff.String(), nil
+}
@@ -0,0 +1,60 @@
+package gofasta
+
+import "testing"
+
+func TestGeneticCodeTable_ResolveCodon(t *testing.T) {
+	cases := map[string]string{
+		"ATG": "M",
+		"atg": "M",
+		"AUG": "M",
+		"CTN": "L",
+		"TAR": "*",
+		"MGR": "R",
+		"ATN": "X",
+		"NNN": "X",
+		"A-G": "!",
+		"---": "-",
+		"AG":  "!",
+		"ZZZ": "X",
+	}
+	for codon, exp := range cases {
+		if actual := StandardGeneticCode.ResolveCodon(codon); exp != actual {
+			t.Errorf("ResolveCodon(%#v): expected %#v, actual %#v", codon, exp, actual)
+		}
+	}
+	if exp := "M"; GeneticCodes[2].ResolveCodon("ATR") != exp {
+		t.Errorf("ResolveCodon: expected %#v, actual %#v", exp, GeneticCodes[2].ResolveCodon("ATR"))
+	}
+}
+
+func TestTranslateWithOptions(t *testing.T) {
+	cases := []struct {
+		seq  string
+		opts TranslateOptions
+		exp  string
+	}{
+		{"atgGCNtaaGG", TranslateOptions{}, "MA*!"},
+		{"atgGCNtaaGG", TranslateOptions{TrimPartial: true}, "MA*"},
+		{"atgGCNtaaGG", TranslateOptions{StopAtStop: true}, "MA"},
+		{"CATGAAATGA", TranslateOptions{Frame: 1, TrimPartial: true}, "MK*"},
+		{"TTGAAA", TranslateOptions{StartAsM: true}, "MK"},
+		{"TTGAAA", TranslateOptions{}, "LK"},
+		{"AUGUGA", TranslateOptions{Code: 2}, "MW"},
+		{"ATG---A-GAAA", TranslateOptions{}, "M-!K"},
+	}
+	for _, c := range cases {
+		actual, err := TranslateWithOptions(c.seq, c.opts)
+		if err != nil {
+			t.Fatalf("TranslateWithOptions: unexpected error %v", err)
+		}
+		if c.exp != actual {
+			t.Errorf("TranslateWithOptions(%#v, %+v): expected %#v, actual %#v", c.seq, c.opts, c.exp, actual)
+		}
+	}
+	if _, err := TranslateWithOptions("ATG", TranslateOptions{Frame: 3}); err == nil {
+		t.Errorf("TranslateWithOptions: expected error for invalid frame")
+	}
+	if _, err := TranslateWithOptions("ATG", TranslateOptions{Code: 7}); err == nil {
+		t.Errorf("TranslateWithOptions: expected error for unknown table")
+	}
+}
@@ -0,0 +1,363 @@
+package gofasta
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// twoBitSignature is the first field of a .2bit file.
+const twoBitSignature = 0x1A412743
+
+// twoBitBases maps 2-bit codes to nucleotides.
+const twoBitBases = "TCAG"
+
+// TwoBitBlock is a run of bases in a .2bit sequence. Start is 0-based.
+type TwoBitBlock struct {
+	Start, Size int
+}
+
+// TwoBit provides random access to the sequences of a UCSC .2bit file.
+// Sequences are returned in uppercase with soft-masked regions in
+// lowercase, unless NoMask is set. Hard-masked regions (N-blocks) are
+// returned as 'N'.
+type TwoBit struct {
+	NoMask bool
+
+	r       io.ReaderAt
+	closer  io.Closer
+	order   binary.ByteOrder
+	names   []string
+	offsets map[string]int64
+}
+
+// twoBitRecord is the header of a sequence record in a .2bit file.
+type twoBitRecord struct {
+	size    int
+	nBlocks []TwoBitBlock
+	masks   []TwoBitBlock
+	offset  int64 // offset of the packed bases
+}
+
+// OpenTwoBit opens a .2bit file for random access.
+func OpenTwoBit(path string) (*TwoBit, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := NewTwoBit(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	t.closer = f
+	return t, nil
+}
+
+// NewTwoBit reads the index of a .2bit file from r.
+func NewTwoBit(r io.ReaderAt) (*TwoBit, error) {
+	header := make([]byte, 16)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	t := &TwoBit{r: r, offsets: make(map[string]int64)}
+	switch {
+	case binary.LittleEndian.Uint32(header) == twoBitSignature:
+		t.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(header) == twoBitSignature:
+		t.order = binary.BigEndian
+	default:
+		return nil, errors.New("2bit: invalid signature")
+	}
+	version := t.order.Uint32(header[4:])
+	if version > 1 {
+		return nil, fmt.Errorf("2bit: unsupported version %d", version)
+	}
+	count := int(t.order.Uint32(header[8:]))
+
+	br := bufio.NewReader(io.NewSectionReader(r, 16, 1<<62))
+	offsetSize := 4
+	if version == 1 {
+		offsetSize = 8
+	}
+	buf := make([]byte, 255+offsetSize)
+	for i := 0; i < count; i++ {
+		nameSize, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("2bit: reading index: %w", err)
+		}
+		if _, err = io.ReadFull(br, buf[:int(nameSize)+offsetSize]); err != nil {
+			return nil, fmt.Errorf("2bit: reading index: %w", err)
+		}
+		name := string(buf[:nameSize])
+		var offset int64
+		if version == 1 {
+			offset = int64(t.order.Uint64(buf[nameSize:]))
+		} else {
+			offset = int64(t.order.Uint32(buf[nameSize:]))
+		}
+		t.names = append(t.names, name)
+		t.offsets[name] = offset
+	}
+	return t, nil
+}
+
+// Close closes the underlying file if the TwoBit was created using
+// OpenTwoBit.
+func (t *TwoBit) Close() error {
+	if t.closer != nil {
+		return t.closer.Close()
+	}
+	return nil
+}
+
+// Names returns the names of the sequences in file order.
+func (t *TwoBit) Names() []string {
+	return t.names
+}
+
+// Length returns the number of bases of the named sequence.
+func (t *TwoBit) Length(name string) (int, error) {
+	rec, err := t.record(name)
+	if err != nil {
+		return 0, err
+	}
+	return rec.size, nil
+}
+
+// NBlocks returns the runs of unknown bases (N) of the named sequence.
+func (t *TwoBit) NBlocks(name string) ([]TwoBitBlock, error) {
+	rec, err := t.record(name)
+	if err != nil {
+		return nil, err
+	}
+	return rec.nBlocks, nil
+}
+
+// MaskBlocks returns the soft-masked runs of the named sequence.
+func (t *TwoBit) MaskBlocks(name string) ([]TwoBitBlock, error) {
+	rec, err := t.record(name)
+	if err != nil {
+		return nil, err
+	}
+	return rec.masks, nil
+}
+
+// Sequence retrieves a whole sequence by name.
+func (t *TwoBit) Sequence(name string) (*CharSequence, error) {
+	rec, err := t.record(name)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := t.fetch(rec, 0, rec.size)
+	if err != nil {
+		return nil, err
+	}
+	return NewCharSequence(name, "", seq), nil
+}
+
+// Fetch retrieves the bases from start (inclusive) to end (exclusive) of
+// the named sequence, using 0-based coordinates. An end beyond the length of
+// the sequence is truncated. The ID of the returned sequence is the region
+// in samtools notation.
+func (t *TwoBit) Fetch(name string, start, end int) (*CharSequence, error) {
+	rec, err := t.record(name)
+	if err != nil {
+		return nil, err
+	}
+	if end > rec.size {
+		end = rec.size
+	}
+	if start < 0 || start >= end {
+		return nil, fmt.Errorf("invalid region %s:%d-%d for sequence of length %d", name, start+1, end, rec.size)
+	}
+	seq, err := t.fetch(rec, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return NewCharSequence(fmt.Sprintf("%s:%d-%d", name, start+1, end), "", seq), nil
+}
+
+// record reads the header of the named sequence record.
+func (t *TwoBit) record(name string) (*twoBitRecord, error) {
+	offset, ok := t.offsets[name]
+	if !ok {
+		return nil, fmt.Errorf("sequence %q not found in 2bit file", name)
+	}
+	r := bufio.NewReader(io.NewSectionReader(t.r, offset, 1<<62))
+	readUint32 := func() (int, error) {
+		var v uint32
+		err := binary.Read(r, t.order, &v)
+		return int(v), err
+	}
+	readBlocks := func() ([]TwoBitBlock, error) {
+		n, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]uint32, 2*n)
+		if err = binary.Read(r, t.order, values); err != nil {
+			return nil, err
+		}
+		blocks := make([]TwoBitBlock, n)
+		for i := range blocks {
+			blocks[i] = TwoBitBlock{int(values[i]), int(values[n+i])}
+		}
+		return blocks, nil
+	}
+	rec := new(twoBitRecord)
+	var err error
+	if rec.size, err = readUint32(); err != nil {
+		return nil, fmt.Errorf("2bit: reading %s: %w", name, err)
+	}
+	if rec.nBlocks, err = readBlocks(); err != nil {
+		return nil, fmt.Errorf("2bit: reading %s: %w", name, err)
+	}
+	if rec.masks, err = readBlocks(); err != nil {
+		return nil, fmt.Errorf("2bit: reading %s: %w", name, err)
+	}
+	rec.offset = offset + int64(4+4+8*len(rec.nBlocks)+4+8*len(rec.masks)+4)
+	return rec, nil
+}
+
+// fetch unpacks the bases from start to end and applies the N-blocks and
+// soft mask.
+func (t *TwoBit) fetch(rec *twoBitRecord, start, end int) (string, error) {
+	packed := make([]byte, (end-1)/4-start/4+1)
+	if _, err := t.r.ReadAt(packed, rec.offset+int64(start/4)); err != nil && err != io.EOF {
+		return "", err
+	}
+	seq := make([]byte, end-start)
+	for i := range seq {
+		pos := start + i
+		b := packed[pos/4-start/4]
+		seq[i] = twoBitBases[(b>>uint(6-2*(pos%4)))&3]
+	}
+	applyBlocks := func(blocks []TwoBitBlock, f func(byte) byte) {
+		for _, block := range blocks {
+			from, to := block.Start, block.Start+block.Size
+			if from < start {
+				from = start
+			}
+			if to > end {
+				to = end
+			}
+			for pos := from; pos < to; pos++ {
+				seq[pos-start] = f(seq[pos-start])
+			}
+		}
+	}
+	applyBlocks(rec.nBlocks, func(byte) byte { return 'N' })
+	if !t.NoMask {
+		applyBlocks(rec.masks, func(c byte) byte { return c + 'a' - 'A' })
+	}
+	return string(seq), nil
+}
+
+// WriteTwoBitFile saves the sequences of the alignment, such as genome
+// contigs, to a .2bit file.
+func (a Alignment) WriteTwoBitFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = a.WriteTwoBit(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteTwoBit writes the sequences of the alignment in the UCSC .2bit
+// format to w. Lowercase bases are stored as soft-masked blocks and any
+// character other than A, C, G and T is stored as an N-block. Files larger
+// than 4 GiB are written using the 64-bit offsets of version 1.
+func (a Alignment) WriteTwoBit(w io.Writer) error {
+	type entry struct {
+		seq     string
+		nBlocks []TwoBitBlock
+		masks   []TwoBitBlock
+		size    int64
+	}
+	entries := make([]entry, len(a))
+	indexSize := int64(0)
+	dataSize := int64(0)
+	for i, s := range a {
+		if len(s.ID()) == 0 || len(s.ID()) > 255 {
+			return fmt.Errorf("2bit: invalid sequence name %q", s.ID())
+		}
+		seq := s.Sequence()
+		e := entry{seq: seq}
+		e.nBlocks = findBlocks(seq, func(c byte) bool { return !strings.ContainsRune("ACGTacgt", rune(c)) })
+		e.masks = findBlocks(seq, func(c byte) bool { return c >= 'a' && c <= 'z' })
+		e.size = int64(4 + 4 + 8*len(e.nBlocks) + 4 + 8*len(e.masks) + 4 + (len(seq)+3)/4)
+		entries[i] = e
+		indexSize += int64(1 + len(s.ID()) + 4)
+		dataSize += e.size
+	}
+	version, offsetSize := uint32(0), int64(4)
+	if 16+indexSize+dataSize > 1<<32-1 {
+		version, offsetSize = 1, 8
+		indexSize += int64(len(a)) * 4
+	}
+
+	bw := bufio.NewWriter(w)
+	order := binary.LittleEndian
+	binary.Write(bw, order, []uint32{twoBitSignature, version, uint32(len(a)), 0})
+	offset := 16 + indexSize
+	for i, s := range a {
+		bw.WriteByte(byte(len(s.ID())))
+		bw.WriteString(s.ID())
+		if offsetSize == 8 {
+			binary.Write(bw, order, uint64(offset))
+		} else {
+			binary.Write(bw, order, uint32(offset))
+		}
+		offset += entries[i].size
+	}
+	for _, e := range entries {
+		binary.Write(bw, order, uint32(len(e.seq)))
+		for _, blocks := range [][]TwoBitBlock{e.nBlocks, e.masks} {
+			binary.Write(bw, order, uint32(len(blocks)))
+			for _, b := range blocks {
+				binary.Write(bw, order, uint32(b.Start))
+			}
+			for _, b := range blocks {
+				binary.Write(bw, order, uint32(b.Size))
+			}
+		}
+		binary.Write(bw, order, uint32(0))
+		packed := make([]byte, (len(e.seq)+3)/4)
+		for i := 0; i < len(e.seq); i++ {
+			code := strings.IndexByte(twoBitBases, e.seq[i]&^0x20)
+			if code < 0 {
+				code = 0
+			}
+			packed[i/4] |= byte(code) << uint(6-2*(i%4))
+		}
+		if _, err := bw.Write(packed); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// findBlocks returns the runs of characters in seq for which match is true.
+func findBlocks(seq string, match func(byte) bool) (blocks []TwoBitBlock) {
+	start := -1
+	for i := 0; i <= len(seq); i++ {
+		if i < len(seq) && match(seq[i]) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			blocks = append(blocks, TwoBitBlock{start, i - start})
+			start = -1
+		}
+	}
+	return
+}
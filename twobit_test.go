@@ -0,0 +1,115 @@
+package gofasta
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func twoBitAlignment() Alignment {
+	return Alignment{
+		NewCharSequence("chr1", "", "ACGTacgtNNNNACG"),
+		NewCharSequence("chr2", "", "nnTTGCAa"),
+	}
+}
+
+func TestAlignment_WriteTwoBit(t *testing.T) {
+	var buff bytes.Buffer
+	if err := (Alignment{NewCharSequence("s", "", "ACGT")}).WriteTwoBit(&buff); err != nil {
+		t.Fatalf("WriteTwoBit: unexpected error %v", err)
+	}
+	exp := []byte{
+		0x43, 0x27, 0x41, 0x1A, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0,
+		1, 's', 22, 0, 0, 0,
+		4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x9C,
+	}
+	if !bytes.Equal(exp, buff.Bytes()) {
+		t.Errorf("WriteTwoBit: expected %#v, actual %#v", exp, buff.Bytes())
+	}
+}
+
+func TestTwoBit(t *testing.T) {
+	var buff bytes.Buffer
+	if err := twoBitAlignment().WriteTwoBit(&buff); err != nil {
+		t.Fatalf("WriteTwoBit: unexpected error %v", err)
+	}
+	tb, err := NewTwoBit(bytes.NewReader(buff.Bytes()))
+	if err != nil {
+		t.Fatalf("NewTwoBit: unexpected error %v", err)
+	}
+	if exp := []string{"chr1", "chr2"}; !reflect.DeepEqual(exp, tb.Names()) {
+		t.Errorf("Names: expected %#v, actual %#v", exp, tb.Names())
+	}
+	for _, s := range twoBitAlignment() {
+		seq, err := tb.Sequence(s.ID())
+		if err != nil {
+			t.Fatalf("Sequence: unexpected error %v", err)
+		}
+		if s.Sequence() != seq.Sequence() {
+			t.Errorf("Sequence: expected %#v, actual %#v", s.Sequence(), seq.Sequence())
+		}
+	}
+	if n, _ := tb.Length("chr1"); n != 15 {
+		t.Errorf("Length: expected %d, actual %d", 15, n)
+	}
+	nBlocks, _ := tb.NBlocks("chr1")
+	if exp := []TwoBitBlock{{8, 4}}; !reflect.DeepEqual(exp, nBlocks) {
+		t.Errorf("NBlocks: expected %#v, actual %#v", exp, nBlocks)
+	}
+	masks, _ := tb.MaskBlocks("chr2")
+	if exp := []TwoBitBlock{{0, 2}, {7, 1}}; !reflect.DeepEqual(exp, masks) {
+		t.Errorf("MaskBlocks: expected %#v, actual %#v", exp, masks)
+	}
+}
+
+func TestTwoBit_Fetch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "genome.2bit")
+	if err := twoBitAlignment().WriteTwoBitFile(path); err != nil {
+		t.Fatalf("WriteTwoBitFile: unexpected error %v", err)
+	}
+	tb, err := OpenTwoBit(path)
+	if err != nil {
+		t.Fatalf("OpenTwoBit: unexpected error %v", err)
+	}
+	defer tb.Close()
+	cases := []struct {
+		name       string
+		start, end int
+		id, seq    string
+	}{
+		{"chr1", 0, 3, "chr1:1-3", "ACG"},
+		{"chr1", 5, 10, "chr1:6-10", "cgtNN"},
+		{"chr1", 11, 100, "chr1:12-15", "NACG"},
+		{"chr2", 1, 3, "chr2:2-3", "nT"},
+	}
+	for _, c := range cases {
+		seq, err := tb.Fetch(c.name, c.start, c.end)
+		if err != nil {
+			t.Fatalf("Fetch: unexpected error %v", err)
+		}
+		if c.id != seq.ID() || c.seq != seq.Sequence() {
+			t.Errorf("Fetch: expected %#v %#v, actual %#v %#v", c.id, c.seq, seq.ID(), seq.Sequence())
+		}
+	}
+	tb.NoMask = true
+	if seq, _ := tb.Fetch("chr1", 5, 10); seq.Sequence() != "CGTNN" {
+		t.Errorf("Fetch: expected %#v, actual %#v", "CGTNN", seq.Sequence())
+	}
+	if _, err := tb.Fetch("chr3", 0, 1); err == nil {
+		t.Errorf("Fetch: expected error for unknown sequence")
+	}
+	if _, err := tb.Fetch("chr1", 10, 5); err == nil {
+		t.Errorf("Fetch: expected error for invalid region")
+	}
+}
+
+func TestNewTwoBit_InvalidSignature(t *testing.T) {
+	if _, err := NewTwoBit(bytes.NewReader(make([]byte, 16))); err == nil {
+		t.Errorf("NewTwoBit: expected error for invalid signature")
+	}
+	if _, err := OpenTwoBit(filepath.Join(os.TempDir(), "missing.2bit")); err == nil {
+		t.Errorf("OpenTwoBit: expected error for missing file")
+	}
+}
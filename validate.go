@@ -0,0 +1,86 @@
+package gofasta
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IssueKind identifies the type of problem found while validating a file.
+type IssueKind int
+
+// Kinds of problems reported by the FASTA reader in strict mode.
+const (
+	IssueOrphanSequence IssueKind = iota
+	IssueEmptyRecord
+	IssueMissingID
+	IssueDuplicateID
+	IssueIllegalChar
+	IssueCarriageReturn
+	IssueCodonLength
+)
+
+var issueKindNames = map[IssueKind]string{
+	IssueOrphanSequence: "orphan sequence",
+	IssueEmptyRecord:    "empty record",
+	IssueMissingID:      "missing ID",
+	IssueDuplicateID:    "duplicate ID",
+	IssueIllegalChar:    "illegal character",
+	IssueCarriageReturn: "carriage return",
+	IssueCodonLength:    "codon length",
+}
+
+func (k IssueKind) String() string {
+	if name, ok := issueKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("IssueKind(%d)", int(k))
+}
+
+// ValidationIssue describes a single problem found in a file.
+// Line and Column are 1-based positions. Column counts characters, not bytes.
+// ID is the identifier of the record the problem belongs to, if any.
+type ValidationIssue struct {
+	Line    int
+	Column  int
+	ID      string
+	Kind    IssueKind
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	if len(i.ID) > 0 {
+		return fmt.Sprintf("%d:%d: %s (record %q): %s", i.Line, i.Column, i.Kind, i.ID, i.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Kind, i.Message)
+}
+
+// ValidationError is returned by a reader in strict mode when one or more
+// problems were found in the input. Issues lists every problem in the order
+// it was found.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return fmt.Sprintf("%d validation issue(s):\n%s", len(e.Issues), strings.Join(lines, "\n"))
+}
+
+// ValidateFasta reads FASTA-formatted records from r in strict mode and
+// returns every problem found. If validChars is not empty, sequence
+// characters not in validChars are reported as illegal.
+// The returned error is non-nil only if the input could not be read.
+func ValidateFasta(r io.Reader, validChars string) ([]ValidationIssue, error) {
+	reader := NewFastaReader(r, false)
+	reader.SetStrict(validChars)
+	for reader.Next() {
+	}
+	if _, ok := reader.Err().(*ValidationError); !ok && reader.Err() != nil {
+		return reader.Issues(), reader.Err()
+	}
+	return reader.Issues(), nil
+}
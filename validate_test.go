@@ -0,0 +1,68 @@
+package gofasta
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateFasta(t *testing.T) {
+	r := strings.NewReader("ACGT\n" +
+		">a test\n" +
+		"ACGT\n" +
+		">b\n" +
+		">a\n" +
+		"ACXT\r\n" +
+		">\n" +
+		"ACGT\n" +
+		">c\n")
+	exp := []ValidationIssue{
+		{Line: 1, Column: 1, Kind: IssueOrphanSequence},
+		{Line: 4, Column: 1, ID: "b", Kind: IssueEmptyRecord},
+		{Line: 5, Column: 2, ID: "a", Kind: IssueDuplicateID},
+		{Line: 6, Column: 5, ID: "a", Kind: IssueCarriageReturn},
+		{Line: 6, Column: 3, ID: "a", Kind: IssueIllegalChar},
+		{Line: 7, Column: 2, Kind: IssueMissingID},
+		{Line: 9, Column: 1, ID: "c", Kind: IssueEmptyRecord},
+	}
+	issues, err := ValidateFasta(r, "ACGT-")
+	if err != nil {
+		t.Fatalf("ValidateFasta: unexpected error %v", err)
+	}
+	if len(issues) != len(exp) {
+		t.Fatalf("ValidateFasta: expected %d issues, actual %d: %v", len(exp), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if issue.Line != exp[i].Line || issue.Column != exp[i].Column || issue.ID != exp[i].ID || issue.Kind != exp[i].Kind {
+			t.Errorf("ValidateFasta: issue %d expected %v, actual %v", i, exp[i], issue)
+		}
+	}
+}
+
+func TestValidateFasta_Valid(t *testing.T) {
+	r := strings.NewReader("; comment\n>a\nacgt\nAC\n\n>b\nAC-T\n")
+	issues, err := ValidateFasta(r, "ACGT-")
+	if err != nil || len(issues) != 0 {
+		t.Errorf("ValidateFasta: expected no issues, actual %v, %v", issues, err)
+	}
+}
+
+func TestFastaReader_SetStrict(t *testing.T) {
+	r := strings.NewReader(">a\nATGGC\n>b\nATGGCG\n")
+	reader := NewFastaReader(r, true)
+	reader.SetStrict("")
+	var ids []string
+	for reader.Next() {
+		ids = append(ids, reader.Record().ID())
+	}
+	if len(ids) != 1 || ids[0] != "b" {
+		t.Errorf("SetStrict: expected only record b, actual %v", ids)
+	}
+	var verr *ValidationError
+	if !errors.As(reader.Err(), &verr) {
+		t.Fatalf("SetStrict: expected *ValidationError, actual %v", reader.Err())
+	}
+	if len(verr.Issues) != 1 || verr.Issues[0].Kind != IssueCodonLength || verr.Issues[0].ID != "a" {
+		t.Errorf("SetStrict: expected codon length issue for a, actual %v", verr.Issues)
+	}
+}
@@ -0,0 +1,102 @@
+package gofasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// FastaWriter writes sequences in the FASTA format to an io.Writer one
+// record at a time.
+//
+// LineWidth sets the maximum number of characters written per sequence line.
+// Common values are 60, 70 and 80. If LineWidth is 0, each sequence is
+// written on a single line. If OmitDescription is true, only the ID is
+// written in the header line. If WriteAttributes is true, the attributes
+// of each sequence are appended to the header line as key=value pairs
+// separated by ';', sorted by key. Values are formatted using %v. The
+// header line can be read back using KeyValueHeader only if the sequence
+// has no description or OmitDescription is true, since a description is
+// not written as a key=value pair.
+//
+// Output is buffered; Flush must be called after the last record to ensure
+// that all data has been written to the underlying io.Writer.
+type FastaWriter struct {
+	LineWidth       int
+	OmitDescription bool
+	WriteAttributes bool
+
+	w *bufio.Writer
+}
+
+// NewFastaWriter creates a new FastaWriter that writes unwrapped sequences
+// to w.
+func NewFastaWriter(w io.Writer) *FastaWriter {
+	return &FastaWriter{w: bufio.NewWriter(w)}
+}
+
+// Write writes a single sequence record.
+func (w *FastaWriter) Write(s Sequence) error {
+	if err := w.writeHeader(s); err != nil {
+		return err
+	}
+	return w.writeSequence(s.Sequence())
+}
+
+// WriteAlignment writes all sequences in the alignment and flushes the
+// output.
+func (w *FastaWriter) WriteAlignment(a Alignment) error {
+	for _, s := range a {
+		if err := w.Write(s); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *FastaWriter) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *FastaWriter) writeHeader(s Sequence) error {
+	w.w.WriteByte('>')
+	w.w.WriteString(s.ID())
+	if !w.OmitDescription && len(s.Description()) > 0 {
+		w.w.WriteByte(' ')
+		w.w.WriteString(s.Description())
+	}
+	if attrs := s.Attributes(); w.WriteAttributes && len(attrs) > 0 {
+		pairs := make([]string, 0, len(attrs))
+		for _, key := range sortedKeys(attrs) {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, attrs[key]))
+		}
+		w.w.WriteByte(' ')
+		w.w.WriteString(strings.Join(pairs, ";"))
+	}
+	return w.w.WriteByte('\n')
+}
+
+// writeSequence writes the sequence wrapped at LineWidth characters.
+// Widths are counted in characters instead of bytes.
+func (w *FastaWriter) writeSequence(seq string) error {
+	if w.LineWidth <= 0 {
+		w.w.WriteString(seq)
+		return w.w.WriteByte('\n')
+	}
+	for len(seq) > 0 {
+		i := 0
+		for n := 0; n < w.LineWidth && i < len(seq); n++ {
+			_, size := utf8.DecodeRuneInString(seq[i:])
+			i += size
+		}
+		w.w.WriteString(seq[:i])
+		if err := w.w.WriteByte('\n'); err != nil {
+			return err
+		}
+		seq = seq[i:]
+	}
+	return nil
+}
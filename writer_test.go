@@ -0,0 +1,93 @@
+package gofasta
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFastaWriter(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("test1", "test", "TTT---TTCTTATTG"),
+		NewCharSequence("test2", "", "TTT---TTCTTTTTG"),
+	}
+	var buff bytes.Buffer
+	w := NewFastaWriter(&buff)
+	if err := w.WriteAlignment(a); err != nil {
+		t.Fatalf("WriteAlignment: unexpected error %v", err)
+	}
+	if exp := a.ToFasta(); exp != buff.String() {
+		t.Errorf("WriteAlignment: expected %#v, actual %#v", exp, buff.String())
+	}
+}
+
+func TestFastaWriter_LineWidth(t *testing.T) {
+	a := Alignment{
+		NewCharSequence("test1", "test", "TTT---TTCTTATTG"),
+		NewCharSequence("test2", "abc", "TTTTT"),
+	}
+	var buff bytes.Buffer
+	w := NewFastaWriter(&buff)
+	w.LineWidth = 5
+	w.OmitDescription = true
+	if err := w.WriteAlignment(a); err != nil {
+		t.Fatalf("WriteAlignment: unexpected error %v", err)
+	}
+	exp := ">test1\nTTT--\n-TTCT\nTATTG\n>test2\nTTTTT\n"
+	if exp != buff.String() {
+		t.Errorf("WriteAlignment: expected %#v, actual %#v", exp, buff.String())
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestFastaWriter_Error(t *testing.T) {
+	w := NewFastaWriter(errWriter{})
+	if err := w.WriteAlignment(Alignment{NewCharSequence("a", "", "ACGT")}); err == nil {
+		t.Errorf("WriteAlignment: expected error from underlying writer")
+	}
+}
+
+func TestFastaWriter_WriteAttributes(t *testing.T) {
+	s := NewCharSequence("test1", "desc", "ATG")
+	s.SetAttribute("reads", 10)
+	s.SetAttribute("country", "Japan")
+	var buff bytes.Buffer
+	w := NewFastaWriter(&buff)
+	w.WriteAttributes = true
+	if err := w.WriteAlignment(Alignment{s, NewCharSequence("test2", "", "ATG")}); err != nil {
+		t.Fatalf("WriteAlignment: unexpected error %v", err)
+	}
+	exp := ">test1 desc country=Japan;reads=10\nATG\n>test2\nATG\n"
+	if exp != buff.String() {
+		t.Errorf("WriteAlignment: expected %#v, actual %#v", exp, buff.String())
+	}
+}
+
+func TestFastaWriter_WriteAttributesRoundTrip(t *testing.T) {
+	s := NewCharSequence("test1", "desc", "ATG")
+	s.SetAttribute("reads", 10)
+	s.SetAttribute("country", "Japan")
+	var buff bytes.Buffer
+	w := NewFastaWriter(&buff)
+	w.OmitDescription = true
+	w.WriteAttributes = true
+	if err := w.WriteAlignment(Alignment{s}); err != nil {
+		t.Fatalf("WriteAlignment: unexpected error %v", err)
+	}
+	line := strings.SplitN(buff.String(), "\n", 2)[0]
+	h, err := KeyValueHeader{}.Parse(line[1:])
+	if err != nil {
+		t.Fatalf("Parse: unexpected error %v", err)
+	}
+	exp := Header{{"id", "test1"}, {"country", "Japan"}, {"reads", "10"}}
+	if !reflect.DeepEqual(exp, h) {
+		t.Errorf("Parse: expected %#v, actual %#v", exp, h)
+	}
+}